@@ -0,0 +1,154 @@
+package cipher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Predefined alphabets available through AlphabetByName
+const (
+	// EnglishSymbols is the 26-letter English alphabet
+	EnglishSymbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// SpanishSymbols is the 27-letter Spanish alphabet
+	SpanishSymbols = "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"
+	// English29Symbols is the English alphabet plus space, period and question mark. Its size is
+	// prime, which is common in textbooks.
+	English29Symbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZ .?"
+	// AlphanumericSymbols is the English alphabet plus the decimal digits
+	AlphanumericSymbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// PrintableASCIISymbols is every printable ASCII character, from space to tilde
+	PrintableASCIISymbols = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+	// Base64Symbols is the standard base64 alphabet (RFC 4648)
+	Base64Symbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	// BinarySymbols is the binary alphabet
+	BinarySymbols = "01"
+	// DNASymbols is the alphabet of DNA nucleotides
+	DNASymbols = "ACGT"
+)
+
+var (
+	// registryMu guards registry
+	registryMu sync.RWMutex
+	// registry maps (lowercase) alphabet names to their symbols
+	registry = map[string]string{
+		"english":      EnglishSymbols,
+		"spanish":      SpanishSymbols,
+		"english29":    English29Symbols,
+		"alphanumeric": AlphanumericSymbols,
+		"ascii":        PrintableASCIISymbols,
+		"base64":       Base64Symbols,
+		"binary":       BinarySymbols,
+		"dna":          DNASymbols,
+	}
+)
+
+// AlphabetByName returns the alphabet registered with the given name, either predefined
+// (english, spanish, english29, alphanumeric, ascii, base64, binary or dna) or registered through
+// RegisterAlphabet. Names are case insensitive.
+func AlphabetByName(name string) (*Alphabet, error) {
+	registryMu.RLock()
+	symbols, found := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("alphabet %q is not registered", name)
+	}
+	return NewAlphabet(symbols), nil
+}
+
+// RegisterAlphabet makes the alphabet defined by symbols available through AlphabetByName. Symbols
+// are parsed by ParseAlphabet, so ranges like "A-Z0-9" are supported. Returns an error if name is
+// empty or already registered, or if symbols is not a valid alphabet of at least 2 symbols.
+func RegisterAlphabet(name, symbols string) error {
+	if name == "" {
+		return fmt.Errorf("alphabet name cannot be empty")
+	}
+	alphabet, err := ParseAlphabet(symbols)
+	if err != nil {
+		return fmt.Errorf("cannot register alphabet %q; %v", name, err)
+	}
+	if n := len(alphabet.symbols); n < 2 {
+		return fmt.Errorf("alphabet must contain at least 2 symbols, got %d", n)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	key := strings.ToLower(name)
+	if _, found := registry[key]; found {
+		return fmt.Errorf("alphabet %q is already registered", name)
+	}
+	registry[key] = alphabet.String()
+	return nil
+}
+
+// AlphabetNames returns the names of every registered alphabet in lexicographic order.
+func AlphabetNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasPrimeSize returns whether the number of symbols of the alphabet is prime. In that case Zn is
+// a field, so every matrix whose determinant is not a multiple of n is a valid key.
+func (a *Alphabet) HasPrimeSize() bool {
+	factors := Factorize(len(a.symbols))
+	return len(factors) == 1 && factors[0].Exp == 1
+}
+
+// ParseAlphabet returns the alphabet defined by s, where ranges of symbols are written as their
+// first and last symbols separated by a hyphen, e.g. "A-Z0-9" is the alphanumeric alphabet. A
+// hyphen at the beginning or end of s is a literal hyphen, as is any symbol preceded by a
+// backslash. Returns an error if s has invalid ranges or repeats symbols, listing every duplicate.
+func ParseAlphabet(s string) (*Alphabet, error) {
+	runes := []rune(s)
+	i := 0
+	// symbol returns the symbol at position i, unescaping it if needed, and moves i past it
+	symbol := func() (rune, error) {
+		if runes[i] == '\\' {
+			if i++; i == len(runes) {
+				return 0, fmt.Errorf("alphabet %q ends with an incomplete escape sequence", s)
+			}
+		}
+		i++
+		return runes[i-1], nil
+	}
+	var symbols []rune
+	for i < len(runes) {
+		first, err := symbol()
+		if err != nil {
+			return nil, err
+		}
+		if i+1 >= len(runes) || runes[i] != '-' {
+			symbols = append(symbols, first)
+			continue
+		}
+		i++ // Skip hyphen
+		last, err := symbol()
+		if err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, fmt.Errorf("got invalid range %q-%q in alphabet %q", first, last, s)
+		}
+		for r := first; r <= last; r++ {
+			symbols = append(symbols, r)
+		}
+	}
+
+	count := make(map[rune]int, len(symbols))
+	var duplicates []string
+	for _, r := range symbols {
+		if count[r]++; count[r] == 2 {
+			duplicates = append(duplicates, fmt.Sprintf("%q", r))
+		}
+	}
+	if len(duplicates) > 0 {
+		return nil, fmt.Errorf("alphabet %q has duplicate symbols %s", s, strings.Join(duplicates, ", "))
+	}
+	return NewAlphabet(string(symbols)), nil
+}
@@ -0,0 +1,132 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestAlphabetByName verify predefined alphabets are found by name
+func TestAlphabetByName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantSize  int
+		wantPrime bool
+	}{
+		{name: "english", wantSize: 26},
+		{name: "Spanish", wantSize: 27},
+		{name: "ENGLISH29", wantSize: 29, wantPrime: true},
+		{name: "alphanumeric", wantSize: 36},
+		{name: "ascii", wantSize: 95},
+		{name: "base64", wantSize: 64},
+		{name: "binary", wantSize: 2, wantPrime: true},
+		{name: "dna", wantSize: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alphabet, err := AlphabetByName(test.name)
+			if err != nil {
+				t.Fatalf("AlphabetByName(%q) returned unexpected error; %v", test.name, err)
+			}
+			if got := len(alphabet.Symbols()); got != test.wantSize {
+				t.Errorf("AlphabetByName(%q) has %d symbols, want %d", test.name, got, test.wantSize)
+			}
+			if got := alphabet.HasPrimeSize(); got != test.wantPrime {
+				t.Errorf("AlphabetByName(%q).HasPrimeSize() = %t, want %t", test.name, got, test.wantPrime)
+			}
+		})
+	}
+	if _, err := AlphabetByName("klingon"); err == nil {
+		t.Errorf("AlphabetByName(%q) returned nil error, want non-nil", "klingon")
+	}
+}
+
+// TestRegisterAlphabet verify user alphabets are registered and validated
+func TestRegisterAlphabet(t *testing.T) {
+	if err := RegisterAlphabet("Vowels", "AEIOU"); err != nil {
+		t.Fatalf("RegisterAlphabet(%q) returned unexpected error; %v", "Vowels", err)
+	}
+	alphabet, err := AlphabetByName("vowels")
+	if err != nil {
+		t.Fatalf("AlphabetByName(%q) returned unexpected error; %v", "vowels", err)
+	}
+	if got := alphabet.String(); got != "AEIOU" {
+		t.Errorf("AlphabetByName(%q) = %q, want %q", "vowels", got, "AEIOU")
+	}
+	wantNames := []string{"alphanumeric", "ascii", "base64", "binary", "dna", "english", "english29", "spanish", "vowels"}
+	if diff := cmp.Diff(wantNames, AlphabetNames()); diff != "" {
+		t.Errorf("AlphabetNames() diff want -> got\n%s", diff)
+	}
+
+	tests := []struct {
+		name, alphabetName, symbols string
+	}{
+		{name: "empty name", alphabetName: "", symbols: "AB"},
+		{name: "single symbol", alphabetName: "unary", symbols: "A"},
+		{name: "already registered", alphabetName: "VOWELS", symbols: "AB"},
+		{name: "predefined", alphabetName: "english", symbols: "AB"},
+		{name: "duplicate symbols", alphabetName: "duplicates", symbols: "ABA"},
+		{name: "single symbol range", alphabetName: "unary", symbols: "A-A"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := RegisterAlphabet(test.alphabetName, test.symbols); err == nil {
+				t.Errorf("RegisterAlphabet(%q, %q) returned nil error, want non-nil", test.alphabetName, test.symbols)
+			}
+		})
+	}
+}
+
+// TestParseAlphabet verify alphabets are parsed with ranges and escaped symbols
+func TestParseAlphabet(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{name: "symbols", data: "ABC", want: "ABC"},
+		{name: "single range", data: "A-Z", want: EnglishSymbols},
+		{name: "several ranges", data: "A-Z0-9", want: AlphanumericSymbols},
+		{name: "ranges and symbols", data: "A-NÑO-Z", want: SpanishSymbols},
+		{name: "single symbol range", data: "A-A", want: "A"},
+		{name: "leading hyphen", data: "-AB", want: "-AB"},
+		{name: "trailing hyphen", data: "AB-", want: "AB-"},
+		{name: "escaped hyphen", data: `A\-B`, want: "A-B"},
+		{name: "escaped backslash", data: `A\\B`, want: `A\B`},
+		{name: "range with escaped bounds", data: `\--\/`, want: "-./"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseAlphabet(test.data)
+			if err != nil {
+				t.Fatalf("ParseAlphabet(%q) returned unexpected error; %v", test.data, err)
+			}
+			if got.String() != test.want {
+				t.Errorf("ParseAlphabet(%q) = %q, want %q", test.data, got, test.want)
+			}
+		})
+	}
+}
+
+// TestParseAlphabet_Error verify invalid alphabets are rejected
+func TestParseAlphabet_Error(t *testing.T) {
+	tests := []struct {
+		name, data, wantErr string
+	}{
+		{name: "duplicate symbols", data: "ABCAB", wantErr: `duplicate symbols 'A', 'B'`},
+		{name: "overlapping ranges", data: "A-ZAAA", wantErr: `duplicate symbols 'A'`},
+		{name: "reversed range", data: "Z-A", wantErr: "invalid range"},
+		{name: "incomplete escape", data: `AB\`, wantErr: "incomplete escape"},
+		{name: "incomplete escape in range", data: `A-\`, wantErr: "incomplete escape"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseAlphabet(test.data)
+			if err == nil {
+				t.Fatalf("ParseAlphabet(%q) returned nil error, want non-nil", test.data)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("ParseAlphabet(%q) returned error %q, want it to contain %q", test.data, err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package attack
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pablotrinidad/hillcipher/cipher"
+)
+
+const (
+	// rowCandidates is the number of best scoring decryption rows, per key order unit, that are
+	// combined into candidate keys.
+	rowCandidates = 4
+	// previewLength is the number of symbols of the decrypted text included in candidates.
+	previewLength = 60
+)
+
+// Candidate is a possible key found by a ciphertext-only attack
+type Candidate struct {
+	// Key is the encryption key
+	Key *cipher.Key
+	// Score is the language model score of the text decrypted with Key
+	Score float64
+	// Preview holds the first symbols of the text decrypted with Key
+	Preview string
+}
+
+// scoredRow is a row of the decryption matrix along with the score of the symbols it produces.
+type scoredRow struct {
+	row   []int
+	score float64
+}
+
+// RecoverKeyCiphertextOnly returns up to k candidate keys of the given order, best first, that
+// decrypt ciphertext into the text that model scores the highest. Each symbol of a decrypted
+// block depends only on one row of the decryption matrix, so rows are searched independently
+// (m^n instead of m^(n^2) candidates) and ranked with model.SymbolScore. The best rows are then
+// combined into invertible matrices which are ranked with model.TextScore. Only orders 2 and 3
+// are supported since the row search grows exponentially on the order.
+func RecoverKeyCiphertextOnly(alphabet *cipher.Alphabet, ciphertext string, order int, model LanguageModel, k int) ([]Candidate, error) {
+	mod := len(alphabet.Symbols())
+	if mod < 2 {
+		return nil, fmt.Errorf("alphabet must contain at least 2 symbols, got %d", mod)
+	}
+	if order < 2 || order > 3 {
+		return nil, fmt.Errorf("ciphertext-only search supports orders 2 and 3, got %d", order)
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("got %d candidates, want at least 1", k)
+	}
+	blocks, err := toBlocks(alphabet, ciphertext, order)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext; %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("got empty ciphertext")
+	}
+
+	rows := bestRows(alphabet, blocks, order, model, rowCandidates*order)
+	var candidates []Candidate
+	combineRows(rows, order, nil, make([]bool, len(rows)), func(selected []int) {
+		data := make([]int, 0, order*order)
+		for _, r := range selected {
+			data = append(data, rows[r].row...)
+		}
+		decryption, _ := cipher.NewMatrix(order, data) // Neglect error since size is exact
+		encryption, err := decryption.InverseMod(mod)
+		if err != nil {
+			return
+		}
+		entries := make([]int, 0, order*order)
+		for _, row := range encryption.Data() {
+			entries = append(entries, row...)
+		}
+		key, _ := cipher.NewKey(entries, mod) // Neglect error since inverse is invertible
+		text := decryptBlocks(alphabet, decryption, blocks, mod)
+		preview := text
+		if len(preview) > previewLength {
+			preview = preview[:previewLength]
+		}
+		candidates = append(candidates, Candidate{Key: key, Score: model.TextScore(text), Preview: string(preview)})
+	})
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no invertible key found among the %d best rows", len(rows))
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	return candidates, nil
+}
+
+// bestRows returns the n rows whose dot product with every block produces the symbols scored
+// the highest by model. Rows whose entries share a factor with the modulo are skipped since they
+// can't be part of an invertible matrix.
+func bestRows(alphabet *cipher.Alphabet, blocks [][]int, order int, model LanguageModel, n int) []scoredRow {
+	mod := len(alphabet.Symbols())
+	var rows []scoredRow
+	row := make([]int, order)
+	symbols := make([]rune, len(blocks))
+	for {
+		g := mod
+		for _, x := range row {
+			_, _, g = cipher.EGCD(x, g)
+		}
+		if g == 1 {
+			for b, block := range blocks {
+				var x int
+				for j := range row {
+					x += row[j] * block[j]
+				}
+				symbols[b], _ = alphabet.Itos(x % mod) // Neglect error because mod operation
+			}
+			rows = append(rows, scoredRow{row: append([]int(nil), row...), score: model.SymbolScore(symbols)})
+		}
+		// Advance to the next row as if it were a number in base mod
+		i := 0
+		for i < order && row[i] == mod-1 {
+			row[i] = 0
+			i++
+		}
+		if i == order {
+			break
+		}
+		row[i]++
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].score > rows[j].score
+	})
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+// combineRows calls f with every ordered selection of order distinct row indices.
+func combineRows(rows []scoredRow, order int, selected []int, used []bool, f func([]int)) {
+	if len(selected) == order {
+		f(selected)
+		return
+	}
+	for i := range rows {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		combineRows(rows, order, append(selected, i), used, f)
+		used[i] = false
+	}
+}
+
+// decryptBlocks returns the symbols obtained by multiplying each block by the decryption matrix.
+func decryptBlocks(alphabet *cipher.Alphabet, decryption *cipher.Matrix, blocks [][]int, mod int) []rune {
+	text := make([]rune, 0, len(blocks)*decryption.Order())
+	for _, block := range blocks {
+		v, _ := decryption.VectorProductMod(mod, block...) // Neglect error since size is exact
+		for _, x := range v {
+			r, _ := alphabet.Itos(x) // Neglect error because mod operation
+			text = append(text, r)
+		}
+	}
+	return text
+}
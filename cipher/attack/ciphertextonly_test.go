@@ -0,0 +1,139 @@
+package attack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablotrinidad/hillcipher/cipher"
+)
+
+const (
+	englishText = "ITWASTHEBESTOFTIMESITWASTHEWORSTOFTIMESITWASTHEAGEOFWISDOMITWASTHEAGEOFFOOLISHNESS" +
+		"ITWASTHEEPOCHOFBELIEFITWASTHEEPOCHOFINCREDULITYITWASTHESEASONOFLIGHTITWASTHESEASONOFDARKNESS" +
+		"ITWASTHESPRINGOFHOPEITWASTHEWINTEROFDESPAIRWEHADEVERYTHINGBEFOREUSWEHADNOTHINGBEFOREUS"
+	spanishText = "ENUNLUGARDELAMANCHADECUYONOMBRENOQUIEROACORDARMENOHAMUCHOTIEMPOQUEVIVIAUNHIDALGODELOS" +
+		"DELANZAENASTILLEROADARGAANTIGUAROCINFLACOYGALGOCORREDORUNAOLLADEALGOMASVACAQUECARNERO" +
+		"SALPICONLASMASNOCHESDUELOSYQUEBRANTOSLOSSABADOSLANTEJASLOSVIERNESALGUNPALOMINODEANADIDURA"
+)
+
+// TestRecoverKeyCiphertextOnly verify the best candidate is the encryption key
+func TestRecoverKeyCiphertextOnly(t *testing.T) {
+	tests := []struct {
+		name, alphabet, key, plaintext string
+		model                          LanguageModel
+	}{
+		{
+			name:      "english order 2 bigrams",
+			alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			key:       "HILL",
+			plaintext: englishText,
+			model:     English(),
+		},
+		{
+			name:      "english order 3 bigrams",
+			alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			key:       "GYBNQKURP",
+			plaintext: englishText,
+			model:     English(),
+		},
+		{
+			name:      "spanish order 2 bigrams",
+			alphabet:  "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			key:       "IKEY",
+			plaintext: spanishText,
+			model:     Spanish(),
+		},
+		{
+			name:      "spanish order 3 bigrams",
+			alphabet:  "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			key:       "FORTALEZA",
+			plaintext: spanishText,
+			model:     Spanish(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alphabet := cipher.NewAlphabet(test.alphabet)
+			c, _ := cipher.NewCipher(alphabet)
+			order := 2
+			if len(test.key) == 9 {
+				order = 3
+			}
+			plaintext := test.plaintext[:len(test.plaintext)-len([]rune(test.plaintext))%order]
+			ciphertext, err := c.Encrypt(plaintext, test.key)
+			if err != nil {
+				t.Fatalf("Encrypt(msg:%q, key:%q) returned unexpected error; %v", plaintext, test.key, err)
+			}
+			candidates, err := RecoverKeyCiphertextOnly(alphabet, ciphertext, order, test.model, 3)
+			if err != nil {
+				t.Fatalf("RecoverKeyCiphertextOnly(%q, %d) returned unexpected error; %v", ciphertext, order, err)
+			}
+			if len(candidates) != 3 {
+				t.Errorf("RecoverKeyCiphertextOnly(%q, %d) returned %d candidates, want 3", ciphertext, order, len(candidates))
+			}
+			gotKey, _ := alphabet.KeyString(candidates[0].Key)
+			if gotKey != test.key {
+				t.Errorf("RecoverKeyCiphertextOnly(%q, %d) best key = %q, want %q", ciphertext, order, gotKey, test.key)
+			}
+			if !strings.HasPrefix(plaintext, candidates[0].Preview) || len(candidates[0].Preview) != previewLength {
+				t.Errorf("RecoverKeyCiphertextOnly(%q, %d) best preview = %q, want prefix of %q", ciphertext, order, candidates[0].Preview, plaintext)
+			}
+			for i := 1; i < len(candidates); i++ {
+				if candidates[i].Score > candidates[i-1].Score {
+					t.Errorf("candidates are not sorted by score: %v > %v", candidates[i].Score, candidates[i-1].Score)
+				}
+			}
+		})
+	}
+}
+
+// TestRecoverKeyCiphertextOnly_Error verify validations
+func TestRecoverKeyCiphertextOnly_Error(t *testing.T) {
+	english := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	tests := []struct {
+		name, alphabet, ciphertext string
+		order, k                   int
+	}{
+		{name: "alphabet with one symbol", alphabet: "A", ciphertext: "AAAA", order: 2, k: 1},
+		{name: "order 1", alphabet: english, ciphertext: "AB", order: 1, k: 1},
+		{name: "order 4", alphabet: english, ciphertext: "ABCD", order: 4, k: 1},
+		{name: "no candidates", alphabet: english, ciphertext: "ABCD", order: 2, k: 0},
+		{name: "ciphertext not in alphabet", alphabet: english, ciphertext: "abcd", order: 2, k: 1},
+		{name: "empty ciphertext", alphabet: english, ciphertext: "", order: 2, k: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := RecoverKeyCiphertextOnly(cipher.NewAlphabet(test.alphabet), test.ciphertext, test.order, English(), test.k)
+			if err == nil {
+				t.Errorf("RecoverKeyCiphertextOnly(%q, %d) returned nil error, want non-nil", test.ciphertext, test.order)
+			}
+		})
+	}
+}
+
+// oddModel prefers digits that are odd, regardless of their position
+type oddModel struct{}
+
+// SymbolScore implements LanguageModel
+func (oddModel) SymbolScore(symbols []rune) float64 {
+	var score float64
+	for _, r := range symbols {
+		score += float64((r - '0') % 2)
+	}
+	return score
+}
+
+// TextScore implements LanguageModel
+func (oddModel) TextScore(text []rune) float64 {
+	return 0
+}
+
+// TestRecoverKeyCiphertextOnly_NoInvertibleKey verify an error is returned when best rows are dependent
+func TestRecoverKeyCiphertextOnly_NoInvertibleKey(t *testing.T) {
+	// Blocks (1, 0) and (0, 1) make each row decrypt into its own entries, so the best rows have
+	// only odd entries and every matrix built from them has an even determinant mod 8.
+	_, err := RecoverKeyCiphertextOnly(cipher.NewAlphabet("01234567"), "1001", 2, oddModel{}, 1)
+	if err == nil {
+		t.Errorf("RecoverKeyCiphertextOnly() returned nil error, want non-nil")
+	}
+}
@@ -0,0 +1,47 @@
+package attack
+
+// Letter and bigram frequencies (percentages) of English and Spanish. Values are approximate,
+// taken from commonly published corpus statistics. Only the most common bigrams are listed,
+// the rest are considered rare by the models.
+var (
+	// EnglishUnigrams holds the relative frequency of each English letter.
+	EnglishUnigrams = map[rune]float64{
+		'A': 8.167, 'B': 1.492, 'C': 2.782, 'D': 4.253, 'E': 12.702, 'F': 2.228, 'G': 2.015,
+		'H': 6.094, 'I': 6.966, 'J': 0.153, 'K': 0.772, 'L': 4.025, 'M': 2.406, 'N': 6.749,
+		'O': 7.507, 'P': 1.929, 'Q': 0.095, 'R': 5.987, 'S': 6.327, 'T': 9.056, 'U': 2.758,
+		'V': 0.978, 'W': 2.360, 'X': 0.150, 'Y': 1.974, 'Z': 0.074,
+	}
+
+	// EnglishBigrams holds the relative frequency of the most common English bigrams.
+	EnglishBigrams = map[string]float64{
+		"TH": 3.56, "HE": 3.07, "IN": 2.43, "ER": 2.05, "AN": 1.99, "RE": 1.85, "ON": 1.76,
+		"AT": 1.49, "EN": 1.45, "ND": 1.35, "TI": 1.34, "ES": 1.34, "OR": 1.28, "TE": 1.20,
+		"OF": 1.17, "ED": 1.17, "IS": 1.13, "IT": 1.12, "AL": 1.09, "AR": 1.07, "ST": 1.05,
+		"TO": 1.04, "NT": 1.04, "NG": 0.95, "SE": 0.93, "HA": 0.93, "AS": 0.87, "OU": 0.87,
+		"IO": 0.83, "LE": 0.83, "VE": 0.83, "CO": 0.79, "ME": 0.79, "DE": 0.76, "HI": 0.76,
+		"RI": 0.73, "RO": 0.73, "IC": 0.70, "NE": 0.69, "EA": 0.69, "RA": 0.69, "CE": 0.65,
+		"LI": 0.62, "CH": 0.60, "LL": 0.58, "BE": 0.58, "MA": 0.57, "SI": 0.55, "OM": 0.55,
+		"UR": 0.54,
+	}
+
+	// SpanishUnigrams holds the relative frequency of each letter of the Spanish alphabet,
+	// with accented vowels counted as their unaccented form.
+	SpanishUnigrams = map[rune]float64{
+		'A': 12.027, 'B': 2.215, 'C': 4.019, 'D': 5.010, 'E': 12.614, 'F': 0.692, 'G': 1.768,
+		'H': 0.703, 'I': 6.972, 'J': 0.493, 'K': 0.011, 'L': 4.967, 'M': 3.157, 'N': 6.712,
+		'Ñ': 0.311, 'O': 9.510, 'P': 2.510, 'Q': 0.877, 'R': 6.871, 'S': 7.977, 'T': 4.632,
+		'U': 3.107, 'V': 1.138, 'W': 0.017, 'X': 0.215, 'Y': 1.008, 'Z': 0.467,
+	}
+
+	// SpanishBigrams holds the relative frequency of the most common Spanish bigrams.
+	SpanishBigrams = map[string]float64{
+		"DE": 2.57, "ES": 2.21, "EN": 2.17, "EL": 1.93, "LA": 1.84, "OS": 1.69, "ER": 1.60,
+		"AS": 1.56, "RA": 1.48, "AR": 1.46, "UE": 1.41, "RE": 1.40, "AD": 1.28, "CI": 1.22,
+		"ON": 1.21, "NT": 1.18, "CO": 1.18, "TE": 1.13, "OR": 1.08, "QU": 1.07, "SE": 1.05,
+		"AN": 1.04, "TA": 1.03, "ST": 0.98, "AL": 0.95, "DO": 0.93, "IO": 0.90, "NA": 0.89,
+		"IA": 0.88, "RO": 0.87, "NE": 0.85, "LO": 0.83, "AC": 0.82, "EC": 0.80, "TO": 0.79,
+		"ND": 0.76, "DA": 0.74, "NO": 0.73, "LE": 0.72, "CA": 0.71, "RI": 0.69, "SA": 0.66,
+		"ME": 0.65, "MA": 0.64, "TR": 0.62, "NC": 0.60, "PO": 0.59, "IE": 0.58, "IC": 0.57,
+		"PA": 0.56,
+	}
+)
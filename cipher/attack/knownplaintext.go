@@ -0,0 +1,161 @@
+// Package attack implements cryptanalysis of the Hill Cipher defined in package cipher. It is
+// meant to show why the cipher must not be used for anything but experimentation and
+// educational purposes.
+package attack
+
+import (
+	"fmt"
+
+	"github.com/pablotrinidad/hillcipher/cipher"
+)
+
+// RecoverKeyKnownPlaintext returns the key of the given order that encrypts plaintext into
+// ciphertext. If P is a matrix whose columns are n plaintext blocks and C holds the matching
+// ciphertext blocks, then C = K·P and K = C·P^-1 whenever P is invertible. For composite moduli
+// the key is recovered modulo each prime power of the alphabet size, selecting blocks that are
+// linearly independent modulo that prime, and the results are combined through the CRT.
+func RecoverKeyKnownPlaintext(alphabet *cipher.Alphabet, plaintext, ciphertext string, order int) (*cipher.Key, error) {
+	mod := len(alphabet.Symbols())
+	if mod < 2 {
+		return nil, fmt.Errorf("alphabet must contain at least 2 symbols, got %d", mod)
+	}
+	if order < 2 {
+		return nil, fmt.Errorf("cannot recover key of order %d < 2", order)
+	}
+	pBlocks, err := toBlocks(alphabet, plaintext, order)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plaintext; %w", err)
+	}
+	cBlocks, err := toBlocks(alphabet, ciphertext, order)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext; %w", err)
+	}
+	if len(pBlocks) != len(cBlocks) {
+		return nil, fmt.Errorf("plaintext and ciphertext have different lengths")
+	}
+
+	factors := cipher.Factorize(mod)
+	moduli := make([]int, len(factors))
+	residues := make([][]int, order*order) // residues[e][f] is entry e of the key mod factor f
+	for e := range residues {
+		residues[e] = make([]int, len(factors))
+	}
+	for f, pp := range factors {
+		moduli[f] = pp.Value()
+		partial, err := recoverKeyModPrimePower(pBlocks, cBlocks, order, pp)
+		if err != nil {
+			return nil, err
+		}
+		for e, x := range partial {
+			residues[e][f] = x
+		}
+	}
+
+	data := make([]int, order*order)
+	for e := range data {
+		data[e], _ = cipher.CRT(residues[e], moduli) // Neglect error since prime powers are coprime
+	}
+	key, err := cipher.NewKey(data, mod)
+	if err != nil {
+		return nil, fmt.Errorf("recovered matrix is not a valid key; %v", err)
+	}
+	m, _ := cipher.NewMatrix(order, data) // Neglect error since data has order^2 entries
+	for i, block := range pBlocks {
+		got, _ := m.VectorProductMod(mod, block...) // Neglect error since size is exact
+		for j := range got {
+			if got[j] != cBlocks[i][j] {
+				return nil, fmt.Errorf("plaintext block %d is not encrypted into ciphertext block %d by the recovered key", i, i)
+			}
+		}
+	}
+	return key, nil
+}
+
+// recoverKeyModPrimePower returns the key entries (row-major) modulo pp. Since C = K·P, then
+// C^T = P^T·K^T, so row i of K is (P^T)^-1 applied to the vector of the i-th symbols of the
+// selected ciphertext blocks.
+func recoverKeyModPrimePower(pBlocks, cBlocks [][]int, order int, pp cipher.PrimePower) ([]int, error) {
+	q := pp.Value()
+	selected := independentBlocks(pBlocks, order, pp.Prime)
+	if len(selected) < order {
+		return nil, fmt.Errorf("found %d plaintext blocks linearly independent mod %d, need %d", len(selected), pp.Prime, order)
+	}
+	rows := make([]int, 0, order*order)
+	for _, b := range selected {
+		for _, x := range pBlocks[b] {
+			rows = append(rows, x%q)
+		}
+	}
+	pt, _ := cipher.NewMatrix(order, rows) // Neglect error since size is exact
+	inverse, _ := pt.InverseMod(q)         // Neglect error since blocks are independent mod p, so det is a unit mod p^k
+	key := make([]int, 0, order*order)
+	for i := 0; i < order; i++ {
+		v := make([]int, order)
+		for k, b := range selected {
+			v[k] = cBlocks[b][i]
+		}
+		row, _ := inverse.VectorProductMod(q, v...) // Neglect error since size is exact
+		key = append(key, row...)
+	}
+	return key, nil
+}
+
+// independentBlocks returns the indices of up to order blocks that are linearly independent
+// modulo the prime p. Blocks are picked greedily while keeping a basis in echelon form.
+func independentBlocks(blocks [][]int, order, p int) []int {
+	var selected []int
+	var basis [][]int
+	pivots := make([]int, 0, order)
+	for b, block := range blocks {
+		v := make([]int, order)
+		for i, x := range block {
+			v[i] = x % p
+		}
+		for k, row := range basis {
+			if f := v[pivots[k]]; f != 0 {
+				for j := range v {
+					v[j] = cipher.Residue(v[j]-f*row[j], p)
+				}
+			}
+		}
+		pivot := 0
+		for pivot < order && v[pivot] == 0 {
+			pivot++
+		}
+		if pivot == order {
+			continue
+		}
+		inverse, _ := cipher.ModularInverse(v[pivot], p) // Neglect error since p is prime
+		for j := range v {
+			v[j] = (v[j] * inverse) % p
+		}
+		basis = append(basis, v)
+		pivots = append(pivots, pivot)
+		selected = append(selected, b)
+		if len(selected) == order {
+			break
+		}
+	}
+	return selected
+}
+
+// toBlocks splits text into blocks of the given size holding the index of each symbol.
+func toBlocks(alphabet *cipher.Alphabet, text string, size int) ([][]int, error) {
+	symbols := []rune(text)
+	if len(symbols)%size != 0 {
+		return nil, fmt.Errorf("text length %d is not multiple of %d; %w", len(symbols), size, cipher.ErrLengthNotMultiple)
+	}
+	blocks := make([][]int, 0, len(symbols)/size)
+	for i := 0; i < len(symbols); i += size {
+		block := make([]int, size)
+		for j, r := range symbols[i : i+size] {
+			x, err := alphabet.Stoi(r)
+			if err != nil {
+				return nil, &cipher.SymbolError{Symbol: r, Position: i + j}
+			}
+			block[j] = x
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
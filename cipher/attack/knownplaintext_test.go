@@ -0,0 +1,127 @@
+package attack
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/pablotrinidad/hillcipher/cipher"
+)
+
+// TestRecoverKeyKnownPlaintext verify keys are recovered from plaintext-ciphertext pairs
+func TestRecoverKeyKnownPlaintext(t *testing.T) {
+	tests := []struct {
+		name, alphabet, key, plaintext string
+		order                          int
+	}{
+		{
+			name:      "english order 2 mod 26",
+			alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			key:       "HILL",
+			plaintext: "SHORTEXAMPLE",
+			order:     2,
+		},
+		{
+			name:      "spanish order 3 mod 27",
+			alphabet:  "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			key:       "FORTALEZA",
+			plaintext: "CRIPTOGRAFIAYSEGURIDAD",
+			order:     3,
+		},
+		{
+			name:      "binary order 4 mod 2",
+			alphabet:  "01",
+			key:       "1000110001100011",
+			plaintext: "100001000010000111110000",
+			order:     4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alphabet := cipher.NewAlphabet(test.alphabet)
+			c, _ := cipher.NewCipher(alphabet, cipher.WithPadding(cipher.FillerPadding{Filler: alphabet.Symbols()[0]}))
+			ciphertext, err := c.Encrypt(test.plaintext, test.key)
+			if err != nil {
+				t.Fatalf("Encrypt(msg:%q, key:%q) returned unexpected error; %v", test.plaintext, test.key, err)
+			}
+			padded, _ := cipher.FillerPadding{Filler: alphabet.Symbols()[0]}.Pad([]rune(test.plaintext), test.order, alphabet)
+			key, err := RecoverKeyKnownPlaintext(alphabet, string(padded), ciphertext, test.order)
+			if err != nil {
+				t.Fatalf("RecoverKeyKnownPlaintext(%q, %q, %d) returned unexpected error; %v", string(padded), ciphertext, test.order, err)
+			}
+			gotKey, _ := alphabet.KeyString(key)
+			if gotKey != test.key {
+				t.Errorf("RecoverKeyKnownPlaintext(%q, %q, %d) = %q, want %q", string(padded), ciphertext, test.order, gotKey, test.key)
+			}
+		})
+	}
+}
+
+// TestRecoverKeyKnownPlaintext_GeneratedKeys verify recovery of random keys over composite moduli
+func TestRecoverKeyKnownPlaintext_GeneratedKeys(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, symbols := range []string{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"} {
+		alphabet := cipher.NewAlphabet(symbols)
+		for order := 2; order <= 6; order++ {
+			key, err := cipher.GenerateKey(order, alphabet, rnd)
+			if err != nil {
+				t.Fatalf("GenerateKey(%d, %q) returned unexpected error; %v", order, alphabet, err)
+			}
+			rawKey, _ := alphabet.KeyString(key)
+			plaintext := make([]rune, 10*order*order)
+			for i := range plaintext {
+				plaintext[i] = alphabet.Symbols()[rnd.Intn(len(symbols))]
+			}
+			c, _ := cipher.NewCipher(alphabet)
+			ciphertext, _ := c.Encrypt(string(plaintext), rawKey)
+			gotKey, err := RecoverKeyKnownPlaintext(alphabet, string(plaintext), ciphertext, order)
+			if err != nil {
+				t.Fatalf("RecoverKeyKnownPlaintext(order:%d, mod:%d) returned unexpected error; %v", order, len(symbols), err)
+			}
+			if !gotKey.Matrix().Equal(key.Matrix()) {
+				t.Errorf("RecoverKeyKnownPlaintext(order:%d, mod:%d) =\n%s, want\n%s", order, len(symbols), gotKey, key)
+			}
+		}
+	}
+}
+
+// TestRecoverKeyKnownPlaintext_Error verify validations and unrecoverable inputs
+func TestRecoverKeyKnownPlaintext_Error(t *testing.T) {
+	tests := []struct {
+		name, alphabet, plaintext, ciphertext string
+		order                                 int
+	}{
+		{name: "alphabet with one symbol", alphabet: "A", plaintext: "AAAA", ciphertext: "AAAA", order: 2},
+		{name: "order 1", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "AB", ciphertext: "AB", order: 1},
+		{name: "plaintext not in alphabet", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "abcd", ciphertext: "ABCD", order: 2},
+		{name: "ciphertext not multiple of order", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "ABCD", ciphertext: "ABC", order: 2},
+		{name: "different lengths", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "ABCD", ciphertext: "ABCDEF", order: 2},
+		{name: "dependent plaintext blocks", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "BBCCDD", ciphertext: "ABCDEF", order: 2},
+		{name: "dependent blocks mod 2 only", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "BDDB", ciphertext: "ABCD", order: 2},
+		{name: "recovered matrix not invertible", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "BAAB", ciphertext: "AAAA", order: 2},
+		// HILL encrypts BAAB into HILL, but it does not encrypt CD into AA
+		{name: "inconsistent pairs", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", plaintext: "BAABCD", ciphertext: "HILLAA", order: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alphabet := cipher.NewAlphabet(test.alphabet)
+			if _, err := RecoverKeyKnownPlaintext(alphabet, test.plaintext, test.ciphertext, test.order); err == nil {
+				t.Errorf("RecoverKeyKnownPlaintext(%q, %q, %d) returned nil error, want non-nil", test.plaintext, test.ciphertext, test.order)
+			}
+		})
+	}
+}
+
+// TestRecoverKeyKnownPlaintext_ValidationErrors verify invalid texts are reported with the cipher
+// package errors
+func TestRecoverKeyKnownPlaintext_ValidationErrors(t *testing.T) {
+	alphabet := cipher.NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	_, err := RecoverKeyKnownPlaintext(alphabet, "ABCd", "ABCD", 2)
+	var symbolErr *cipher.SymbolError
+	if !errors.As(err, &symbolErr) || symbolErr.Symbol != 'd' || symbolErr.Position != 3 {
+		t.Errorf("RecoverKeyKnownPlaintext(%q) returned error %v, want unknown symbol 'd' at position 3", "ABCd", err)
+	}
+	if _, err := RecoverKeyKnownPlaintext(alphabet, "ABCD", "ABC", 2); !errors.Is(err, cipher.ErrLengthNotMultiple) {
+		t.Errorf("RecoverKeyKnownPlaintext(%q) returned error %v, want %v", "ABC", err, cipher.ErrLengthNotMultiple)
+	}
+}
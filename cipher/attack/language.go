@@ -0,0 +1,152 @@
+package attack
+
+import "math"
+
+// LanguageModel scores how close a text is to a natural language, higher scores meaning closer.
+type LanguageModel interface {
+	// SymbolScore scores a sequence of symbols that are not necessarily adjacent in the text,
+	// such as every n-th symbol of a message. It is used to rank key rows independently.
+	SymbolScore(symbols []rune) float64
+	// TextScore scores a contiguous text.
+	TextScore(text []rune) float64
+}
+
+// floorProbability is the probability assigned to symbols and bigrams missing from a model.
+const floorProbability = 1e-5
+
+// FrequencyModel scores texts by the mean log-probability of their symbols (letter frequency).
+type FrequencyModel struct {
+	logProb map[rune]float64
+}
+
+// NewFrequencyModel returns a letter frequency model from relative frequencies, which don't
+// need to be normalized.
+func NewFrequencyModel(frequencies map[rune]float64) *FrequencyModel {
+	var total float64
+	for _, f := range frequencies {
+		total += f
+	}
+	logProb := make(map[rune]float64, len(frequencies))
+	for r, f := range frequencies {
+		logProb[r] = math.Log(f / total)
+	}
+	return &FrequencyModel{logProb: logProb}
+}
+
+// SymbolScore implements LanguageModel
+func (m *FrequencyModel) SymbolScore(symbols []rune) float64 {
+	if len(symbols) == 0 {
+		return 0
+	}
+	var score float64
+	for _, r := range symbols {
+		score += m.symbolLogProb(r)
+	}
+	return score / float64(len(symbols))
+}
+
+// TextScore implements LanguageModel
+func (m *FrequencyModel) TextScore(text []rune) float64 {
+	return m.SymbolScore(text)
+}
+
+// symbolLogProb returns the log-probability of r.
+func (m *FrequencyModel) symbolLogProb(r rune) float64 {
+	if p, found := m.logProb[r]; found {
+		return p
+	}
+	return math.Log(floorProbability)
+}
+
+// BigramModel scores symbol sequences by letter frequency and texts by the mean
+// log-probability of their bigrams.
+type BigramModel struct {
+	unigrams      *FrequencyModel
+	bigramLogProb map[string]float64
+}
+
+// NewBigramModel returns a bigram model from relative letter and bigram frequencies, which
+// don't need to be normalized. Bigrams are two-symbol strings.
+func NewBigramModel(unigrams map[rune]float64, bigrams map[string]float64) *BigramModel {
+	var total float64
+	for _, f := range bigrams {
+		total += f
+	}
+	logProb := make(map[string]float64, len(bigrams))
+	for b, f := range bigrams {
+		logProb[b] = math.Log(f / total)
+	}
+	return &BigramModel{unigrams: NewFrequencyModel(unigrams), bigramLogProb: logProb}
+}
+
+// SymbolScore implements LanguageModel
+func (m *BigramModel) SymbolScore(symbols []rune) float64 {
+	return m.unigrams.SymbolScore(symbols)
+}
+
+// TextScore implements LanguageModel
+func (m *BigramModel) TextScore(text []rune) float64 {
+	if len(text) < 2 {
+		return m.unigrams.TextScore(text)
+	}
+	var score float64
+	for i := 1; i < len(text); i++ {
+		if p, found := m.bigramLogProb[string(text[i-1:i+1])]; found {
+			score += p
+		} else {
+			score += math.Log(floorProbability)
+		}
+	}
+	return score / float64(len(text)-1)
+}
+
+// IndexOfCoincidenceModel scores texts by how close their index of coincidence is to the one
+// expected for a language. Unlike frequency models, it doesn't depend on the symbols themselves,
+// only on their distribution.
+type IndexOfCoincidenceModel struct {
+	Target float64
+}
+
+// SymbolScore implements LanguageModel
+func (m IndexOfCoincidenceModel) SymbolScore(symbols []rune) float64 {
+	return -math.Abs(IndexOfCoincidence(symbols) - m.Target)
+}
+
+// TextScore implements LanguageModel
+func (m IndexOfCoincidenceModel) TextScore(text []rune) float64 {
+	return m.SymbolScore(text)
+}
+
+// IndexOfCoincidence returns the probability that two symbols drawn at random from text are equal.
+func IndexOfCoincidence(text []rune) float64 {
+	n := len(text)
+	if n < 2 {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range text {
+		counts[r]++
+	}
+	var sum int
+	for _, c := range counts {
+		sum += c * (c - 1)
+	}
+	return float64(sum) / float64(n*(n-1))
+}
+
+// Expected index of coincidence of English and Spanish texts.
+const (
+	EnglishIoC = 0.0667
+	SpanishIoC = 0.0775
+)
+
+// English returns a bigram model of English texts written in uppercase without spaces.
+func English() LanguageModel {
+	return NewBigramModel(EnglishUnigrams, EnglishBigrams)
+}
+
+// Spanish returns a bigram model of Spanish texts written in uppercase without spaces nor
+// diacritics, matching the 27 letters alphabet (including Ñ).
+func Spanish() LanguageModel {
+	return NewBigramModel(SpanishUnigrams, SpanishBigrams)
+}
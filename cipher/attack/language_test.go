@@ -0,0 +1,70 @@
+package attack
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLanguageModels verify natural texts score higher than scrambled ones
+func TestLanguageModels(t *testing.T) {
+	tests := []struct {
+		name              string
+		model             LanguageModel
+		natural, shuffled string
+		anagram           bool // Shuffled uses natural's letters, so only bigrams can tell them apart
+	}{
+		{name: "english frequency", model: NewFrequencyModel(EnglishUnigrams), natural: englishText, shuffled: "QZXJKVQZXJKWYQZXJ"},
+		{name: "english bigrams", model: English(), natural: englishText, shuffled: "TSITAWHEBTSEOTFIM", anagram: true},
+		{name: "spanish frequency", model: NewFrequencyModel(SpanishUnigrams), natural: spanishText, shuffled: "ÑKWXZQJÑKWXZQJ"},
+		{name: "spanish bigrams", model: Spanish(), natural: spanishText, shuffled: "NEULNUAGDRLEAM", anagram: true},
+		{name: "english index of coincidence", model: IndexOfCoincidenceModel{Target: EnglishIoC}, natural: englishText, shuffled: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{name: "spanish index of coincidence", model: IndexOfCoincidenceModel{Target: SpanishIoC}, natural: spanishText, shuffled: "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			natural, shuffled := test.model.TextScore([]rune(test.natural)), test.model.TextScore([]rune(test.shuffled))
+			if natural <= shuffled {
+				t.Errorf("TextScore(natural) = %v <= TextScore(%q) = %v", natural, test.shuffled, shuffled)
+			}
+			if test.anagram {
+				return
+			}
+			natural, shuffled = test.model.SymbolScore([]rune(test.natural)), test.model.SymbolScore([]rune(test.shuffled))
+			if natural <= shuffled {
+				t.Errorf("SymbolScore(natural) = %v < SymbolScore(%q) = %v", natural, test.shuffled, shuffled)
+			}
+		})
+	}
+}
+
+// TestLanguageModels_ShortTexts verify models handle texts too short to score
+func TestLanguageModels_ShortTexts(t *testing.T) {
+	if s := NewFrequencyModel(EnglishUnigrams).SymbolScore(nil); s != 0 {
+		t.Errorf("SymbolScore(empty) = %v, want 0", s)
+	}
+	if s, want := English().TextScore([]rune("E")), math.Log(EnglishUnigrams['E']/100); math.Abs(s-want) > 1e-3 {
+		t.Errorf("TextScore(%q) = %v, want %v", "E", s, want)
+	}
+	if s, want := English().TextScore([]rune("ñ")), math.Log(floorProbability); s != want {
+		t.Errorf("TextScore(%q) = %v, want %v", "ñ", s, want)
+	}
+}
+
+// TestIndexOfCoincidence verify definition
+func TestIndexOfCoincidence(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{text: "", want: 0},
+		{text: "A", want: 0},
+		{text: "AA", want: 1},
+		{text: "AB", want: 0},
+		{text: "AABB", want: 4.0 / 12},
+	}
+	for _, test := range tests {
+		if got := IndexOfCoincidence([]rune(test.text)); got != test.want {
+			t.Errorf("IndexOfCoincidence(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
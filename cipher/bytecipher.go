@@ -0,0 +1,78 @@
+package cipher
+
+import (
+	"fmt"
+	"io"
+)
+
+// byteMod is the modulo of byte-oriented ciphers, one symbol per byte value.
+const byteMod = 256
+
+// ByteCipher is an instance of the Hill Cipher whose alphabet is every byte value, so it works on
+// arbitrary binary data modulo 256. Since 256 is a power of 2, keys are valid if and only if their
+// determinant is odd. Blocks are encrypted independently, so repeated plaintext blocks produce
+// repeated ciphertext blocks (patterns in images survive encryption).
+type ByteCipher struct {
+	c *Cipher
+}
+
+// NewByteCipher initializes a new cipher over all byte values with the given options.
+func NewByteCipher(opts ...Option) *ByteCipher {
+	symbols := make([]rune, byteMod)
+	for i := range symbols {
+		symbols[i] = rune(i)
+	}
+	c, _ := NewCipher(NewAlphabet(string(symbols)), opts...) // Neglect error since alphabet has 256 symbols
+	return &ByteCipher{c: c}
+}
+
+// bytesToText maps each byte to the symbol with the same value.
+func bytesToText(b []byte) string {
+	symbols := make([]rune, len(b))
+	for i, x := range b {
+		symbols[i] = rune(x)
+	}
+	return string(symbols)
+}
+
+// textToBytes maps each symbol to the byte with the same value.
+func textToBytes(s string) []byte {
+	symbols := []rune(s)
+	b := make([]byte, len(symbols))
+	for i, r := range symbols {
+		b[i] = byte(r)
+	}
+	return b
+}
+
+// EncryptBytes encrypts msg using key, whose length must be a square number. Returns an error if
+// key's determinant is even or if msg length is not multiple of key's order and the cipher has no
+// padding.
+func (b *ByteCipher) EncryptBytes(msg, key []byte) ([]byte, error) {
+	cipherText, err := b.c.Encrypt(bytesToText(msg), bytesToText(key))
+	if err != nil {
+		return nil, err
+	}
+	return textToBytes(cipherText), nil
+}
+
+// DecryptBytes decrypts msg using key, whose length must be a square number. Returns an error if
+// key's determinant is even or if msg length is not multiple of key's order.
+func (b *ByteCipher) DecryptBytes(msg, key []byte) ([]byte, error) {
+	plainText, err := b.c.Decrypt(bytesToText(msg), bytesToText(key))
+	if err != nil {
+		return nil, err
+	}
+	return textToBytes(plainText), nil
+}
+
+// GenerateKey returns a random key of the given order that is invertible modulo 256. If random is
+// nil, crypto/rand.Reader is used.
+func (b *ByteCipher) GenerateKey(order int, random io.Reader) ([]byte, error) {
+	key, err := GenerateKey(order, &b.c.alphabet, random)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate byte key; %v", err)
+	}
+	rawKey, _ := b.c.alphabet.KeyString(key) // Neglect error since entries are residues mod 256
+	return textToBytes(rawKey), nil
+}
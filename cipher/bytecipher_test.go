@@ -0,0 +1,94 @@
+package cipher
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestByteCipher verify binary data is encrypted and decrypted back
+func TestByteCipher(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	data := make([]byte, 3000)
+	rnd.Read(data)
+	generatedKey, err := NewByteCipher().GenerateKey(7, rnd)
+	if err != nil {
+		t.Fatalf("GenerateKey(7) returned unexpected error; %v", err)
+	}
+	defaultSourceKey, err := NewByteCipher().GenerateKey(3, nil)
+	if err != nil {
+		t.Fatalf("GenerateKey(3, nil) returned unexpected error; %v", err)
+	}
+	tests := []struct {
+		name      string
+		msg, key  []byte
+		opts      []Option
+		wantBytes []byte // Skipped if nil
+	}{
+		{
+			name:      "order 2",
+			msg:       []byte{0, 1, 255, 128},
+			key:       []byte{3, 2, 2, 1},
+			wantBytes: []byte{2, 1, 253, 126},
+		},
+		{name: "random data order 3", msg: data, key: []byte{1, 200, 3, 4, 5, 6, 255, 8, 10}},
+		{name: "random data order 7 with padding", msg: data[:2999], key: generatedKey, opts: []Option{WithPadding(LengthPadding{})}},
+		{name: "random data order 3 default source key", msg: data, key: defaultSourceKey},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewByteCipher(test.opts...)
+			cipherText, err := c.EncryptBytes(test.msg, test.key)
+			if err != nil {
+				t.Fatalf("EncryptBytes(key:%v) returned unexpected error; %v", test.key, err)
+			}
+			if test.wantBytes != nil && !bytes.Equal(cipherText, test.wantBytes) {
+				t.Errorf("EncryptBytes(%v, %v) = %v, want %v", test.msg, test.key, cipherText, test.wantBytes)
+			}
+			plainText, err := c.DecryptBytes(cipherText, test.key)
+			if err != nil {
+				t.Fatalf("DecryptBytes(key:%v) returned unexpected error; %v", test.key, err)
+			}
+			if !bytes.Equal(plainText, test.msg) {
+				t.Errorf("DecryptBytes(EncryptBytes(msg)) differs from msg using key %v", test.key)
+			}
+		})
+	}
+}
+
+// TestByteCipher_RepeatedBlocks verify equal plaintext blocks produce equal ciphertext blocks
+func TestByteCipher_RepeatedBlocks(t *testing.T) {
+	c := NewByteCipher()
+	key := []byte{3, 2, 2, 1}
+	cipherText, _ := c.EncryptBytes(bytes.Repeat([]byte{10, 20}, 4), key)
+	if want := bytes.Repeat(cipherText[:2], 4); !bytes.Equal(cipherText, want) {
+		t.Errorf("EncryptBytes(repeated blocks) = %v, want %v", cipherText, want)
+	}
+}
+
+// TestByteCipher_Error verify validations
+func TestByteCipher_Error(t *testing.T) {
+	c := NewByteCipher()
+	tests := []struct {
+		name     string
+		msg, key []byte
+	}{
+		{name: "even determinant", msg: []byte{1, 2}, key: []byte{2, 0, 0, 1}},
+		{name: "non-square key", msg: []byte{1, 2}, key: []byte{1, 0, 1}},
+		{name: "length not multiple of order", msg: []byte{1, 2, 3}, key: []byte{1, 0, 0, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := c.EncryptBytes(test.msg, test.key); err == nil {
+				t.Errorf("EncryptBytes(%v, %v) returned nil error, want non-nil", test.msg, test.key)
+			}
+			if _, err := c.DecryptBytes(test.msg, test.key); err == nil {
+				t.Errorf("DecryptBytes(%v, %v) returned nil error, want non-nil", test.msg, test.key)
+			}
+		})
+	}
+	if _, err := c.GenerateKey(1, strings.NewReader("")); err == nil {
+		t.Errorf("GenerateKey(1) returned nil error, want non-nil")
+	}
+}
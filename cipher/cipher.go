@@ -8,64 +8,168 @@
 package cipher
 
 import (
+	crand "crypto/rand"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
 	"strings"
 )
 
 // Cipher is an instance of the Hill Cipher on an specific alphabet
 type Cipher struct {
-	mod      int
-	alphabet Alphabet
+	mod        int
+	alphabet   Alphabet
+	padding    Padding
+	mode       Mode
+	normalizer *Normalizer
+}
+
+// Option configures optional behavior of a Cipher
+type Option func(*Cipher)
+
+// WithPadding makes the cipher pad messages to a multiple of the key's order before encryption
+// and strip the padding after decryption.
+func WithPadding(p Padding) Option {
+	return func(c *Cipher) {
+		c.padding = p
+	}
 }
 
 // NewCipher initializes new cipher ready for given alphabet
-func NewCipher(alphabet *Alphabet) (*Cipher, error) {
+func NewCipher(alphabet *Alphabet, opts ...Option) (*Cipher, error) {
 	n := len(alphabet.Symbols())
 	if n < 2 {
 		return nil, fmt.Errorf("alphabet must contain at least 2 symbols, got %d", n)
 	}
-	return &Cipher{mod: n, alphabet: *alphabet}, nil
+	c := &Cipher{mod: n, alphabet: *alphabet}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
 
-// verifyKeyTextPair makes sure key and text are usable in the current cipher.
-// Returns Key and message if valid.
-func (c *Cipher) verifyKeyTextPair(rawM, rawK string) (*Matrix, []rune, error) {
-	if !c.alphabet.Belongs(rawM) {
-		return nil, nil, fmt.Errorf("message %q does not belong to alphabet %q", rawM, c.alphabet)
-	}
-	if !c.alphabet.Belongs(rawK) {
-		return nil, nil, fmt.Errorf("key %q does not belong to alphabet %q", rawK, c.alphabet)
+// ParseKey returns the key represented by rawK in the cipher's alphabet, so it can be parsed once
+// and used through EncryptWithKey and DecryptWithKey. Keys of n^2+n symbols are affine keys whose
+// last n symbols are the translation vector. Returns an error if rawK doesn't belong to the
+// alphabet or if it isn't a valid key.
+func (c *Cipher) ParseKey(rawK string) (*Key, error) {
+	if err := c.alphabet.unknownSymbol(rawK); err != nil {
+		return nil, fmt.Errorf("key %q does not belong to alphabet %q; %w", rawK, c.alphabet, err)
 	}
-	msg, k := []rune(rawM), []rune(rawK)
+	k := []rune(rawK)
 	kInt := make([]int, len(k))
 	for i, s := range k {
 		kInt[i], _ = c.alphabet.Stoi(s) // Neglect error because key is permutation of alphabet
 	}
-	key, err := NewKey(kInt, c.mod)
+	var key *Key
+	var err error
+	if n := int(math.Sqrt(float64(len(k)))); n*n+n == len(k) {
+		key, err = NewAffineKey(kInt[:n*n], kInt[n*n:], c.mod)
+	} else {
+		key, err = NewKey(kInt, c.mod)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key for %q; %w", rawK, err)
+	}
+	return key, nil
+}
+
+// verifyKey makes sure key is usable in the current cipher.
+func (c *Cipher) verifyKey(key *Key) error {
+	if !key.matrix.IsInvertibleMod(c.mod) {
+		return fmt.Errorf("key %w modulo %d", ErrNotInvertible, c.mod)
+	}
+	return nil
+}
+
+// verifyText makes sure text can be split in blocks of the given order in the current cipher.
+// The message is normalized first if the cipher has a normalizer. When pad is set and the cipher
+// has a padding scheme, the message is padded before checking its length. Returns the message and
+// the unknown symbols kept by the normalizer if valid.
+func (c *Cipher) verifyText(rawM string, order int, pad bool) ([]rune, []passThrough, error) {
+	msg, kept, err := c.normalize(rawM)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create key for %q; %v", rawK, err)
+		return nil, nil, err
+	}
+	if pad && c.padding != nil {
+		if msg, err = c.padding.Pad(msg, order, &c.alphabet); err != nil {
+			return nil, nil, fmt.Errorf("failed to pad message %q; %w", rawM, err)
+		}
+	}
+	if len(msg)%order != 0 {
+		return nil, nil, fmt.Errorf("%w, consider adding padding", ErrLengthNotMultiple)
 	}
-	if len(msg)%key.order != 0 {
-		return nil, nil, fmt.Errorf("message length is not multiple of key's length, consider adding padding")
+	return msg, kept, nil
+}
+
+// blockMode returns the cipher's mode of operation, ECB by default.
+func (c *Cipher) blockMode() Mode {
+	if c.mode == nil {
+		return ECB{}
 	}
-	mKey := Matrix(*key)
-	return &mKey, msg, nil
+	return c.mode
 }
 
-// performOperations apply cipher matrix operations on the given key and text. It assume all
-// key and message validations were applied before. Returns the resulting string.
-func (c *Cipher) performOperations(key *Matrix, msg []rune) string {
+// encrypter returns the function that encrypts the blocks of a message using key in the
+// cipher's mode of operation. The translation vector of affine keys is added to every block
+// produced by the mode.
+func (c *Cipher) encrypter(key *Key) (BlockFunc, error) {
+	encrypt, err := c.blockMode().Encrypter(&key.matrix, c.mod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mode of operation; %w", err)
+	}
+	if key.shift == nil {
+		return encrypt, nil
+	}
+	return func(block []int) []int {
+		result := encrypt(block)
+		for i, b := range key.shift {
+			result[i] = SumMod(result[i], b, c.mod)
+		}
+		return result
+	}, nil
+}
+
+// decrypter returns the function that decrypts the blocks of a message encrypted using key in the
+// cipher's mode of operation. If inverse is not nil it must be the key matrix inverted mod n, and
+// modes that only need the inverse use it instead of inverting the key. The translation vector of
+// affine keys is subtracted from every block before it is passed to the mode.
+func (c *Cipher) decrypter(key *Key, inverse *Matrix) (BlockFunc, error) {
+	var decrypt BlockFunc
+	var err error
+	if m, ok := c.blockMode().(inverseDecrypter); ok && inverse != nil {
+		decrypt, err = m.inverseDecrypter(inverse, c.mod)
+	} else {
+		decrypt, err = c.blockMode().Decrypter(&key.matrix, c.mod)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mode of operation; %w", err)
+	}
+	if key.shift == nil {
+		return decrypt, nil
+	}
+	return func(block []int) []int {
+		unshifted := make([]int, len(block))
+		for i, b := range key.shift {
+			unshifted[i] = Residue(block[i]-b, c.mod)
+		}
+		return decrypt(unshifted)
+	}, nil
+}
+
+// performOperations apply the block operation on every block of the text. It assume all key and
+// message validations were applied before. Returns the resulting string.
+func (c *Cipher) performOperations(op BlockFunc, order int, msg []rune) string {
 	// Use builder for optimum string creation
 	var result strings.Builder
 
-	for i := 0; i < len(msg); i += key.order {
-		vector := make([]int, key.order)
-		for j, r := range msg[i : i+key.order] {
+	for i := 0; i < len(msg); i += order {
+		vector := make([]int, order)
+		for j, r := range msg[i : i+order] {
 			vector[j], _ = c.alphabet.Stoi(r) // Neglect error because message is permutation of alphabet.
 		}
-		prodVector, _ := key.VectorProductMod(c.mod, vector...) // Neglect error because size is exact
-		for _, ri := range prodVector {
+		for _, ri := range op(vector) {
 			r, _ := c.alphabet.Itos(ri) // Neglect error because mod operation
 			result.WriteRune(r)
 		}
@@ -75,33 +179,132 @@ func (c *Cipher) performOperations(key *Matrix, msg []rune) string {
 
 // Encrypt plain text using given key. Returns an error if either key or message don't belong
 // to the cipher's alphabet, if key is not invertible by cipher's modulo or if message length
-// is not multiple of key's order (matrix order).
+// is not multiple of key's order (matrix order) and the cipher has no padding.
 func (c *Cipher) Encrypt(rawM, rawK string) (string, error) {
-	key, msg, err := c.verifyKeyTextPair(rawM, rawK)
+	key, err := c.ParseKey(rawK)
 	if err != nil {
 		return "", err
 	}
-	return c.performOperations(key, msg), nil
+	return c.EncryptWithKey(rawM, key)
+}
+
+// EncryptWithKey encrypts plain text using a pre-built key. Returns an error if message doesn't
+// belong to the cipher's alphabet, if key is not invertible by cipher's modulo or if message
+// length is not multiple of key's order (matrix order) and the cipher has no padding.
+func (c *Cipher) EncryptWithKey(rawM string, key *Key) (string, error) {
+	if err := c.verifyKey(key); err != nil {
+		return "", err
+	}
+	return c.encrypt(rawM, key)
+}
+
+// encrypt plain text using a key already verified.
+func (c *Cipher) encrypt(rawM string, key *Key) (string, error) {
+	msg, kept, err := c.verifyText(rawM, key.matrix.order, true)
+	if err != nil {
+		return "", err
+	}
+	encrypt, err := c.encrypter(key)
+	if err != nil {
+		return "", err
+	}
+	return restore(c.performOperations(encrypt, key.matrix.order, msg), kept), nil
 }
 
 // Decrypt cipher text using given key. Returns an error if either key or cipher text don't belong
 // to the cipher's alphabet, if key is not invertible by cipher's modulo or if cipher text length
-// is not multiple of key's order (matrix order).
+// is not multiple of key's order (matrix order). If the cipher has padding, it is removed from
+// the result and an error is returned when it is malformed.
 func (c *Cipher) Decrypt(rawM, rawK string) (string, error) {
-	key, plainText, err := c.verifyKeyTextPair(rawM, rawK)
+	key, err := c.ParseKey(rawK)
+	if err != nil {
+		return "", err
+	}
+	return c.DecryptWithKey(rawM, key)
+}
+
+// DecryptWithKey decrypts cipher text using a pre-built key. Returns an error if cipher text
+// doesn't belong to the cipher's alphabet, if key is not invertible by cipher's modulo or if
+// cipher text length is not multiple of key's order (matrix order). If the cipher has padding,
+// it is removed from the result and an error is returned when it is malformed.
+func (c *Cipher) DecryptWithKey(rawM string, key *Key) (string, error) {
+	if err := c.verifyKey(key); err != nil {
+		return "", err
+	}
+	return c.decrypt(rawM, key, nil)
+}
+
+// decrypt cipher text using a key already verified, and its inverse if not nil.
+func (c *Cipher) decrypt(rawM string, key *Key, inverse *Matrix) (string, error) {
+	cipherText, kept, err := c.verifyText(rawM, key.matrix.order, false)
 	if err != nil {
 		return "", err
 	}
-	invertedKey, _ := key.InverseMod(c.mod) // Neglect error since it's checked by key-text verification
-	return c.performOperations(invertedKey, plainText), nil
+	decrypt, err := c.decrypter(key, inverse)
+	if err != nil {
+		return "", err
+	}
+	return c.unpad(c.performOperations(decrypt, key.matrix.order, cipherText), key.matrix.order, kept)
+}
+
+// unpad returns the decrypted plainText without the cipher's padding, if any, and with the kept
+// symbols restored. Returns an error if the padding is malformed.
+func (c *Cipher) unpad(plainText string, order int, kept []passThrough) (string, error) {
+	if c.padding == nil {
+		return restore(plainText, kept), nil
+	}
+	unpadded, err := c.padding.Unpad([]rune(plainText), order, &c.alphabet)
+	if err != nil {
+		return "", fmt.Errorf("failed to remove padding from %q; %w", plainText, err)
+	}
+	return restore(string(unpadded), kept), nil
 }
 
-// Key represents a Hill Cipher key matrix
-type Key Matrix
+// Key represents a Hill Cipher key matrix. Keys of the affine variant also have a translation
+// vector b, so blocks are encrypted as C = K·P + b (mod n).
+type Key struct {
+	matrix Matrix
+	shift  []int
+}
 
-// String makes Key implement Stringer
+// String makes Key implement Stringer. The translation vector, if any, is written after the matrix.
 func (k Key) String() string {
-	return Matrix(k).String()
+	if k.shift == nil {
+		return k.matrix.String()
+	}
+	return k.matrix.String() + "+" + Matrix{order: 1, data: [][]int{k.shift}}.String()
+}
+
+// Matrix returns a copy of the key matrix.
+func (k *Key) Matrix() *Matrix {
+	return &Matrix{order: k.matrix.order, data: k.matrix.Data()}
+}
+
+// Shift returns a copy of the translation vector of affine keys, or nil if the key is not affine.
+func (k *Key) Shift() []int {
+	if k.shift == nil {
+		return nil
+	}
+	return append([]int(nil), k.shift...)
+}
+
+// Inverse returns the key that undoes k modulo mod, i.e. encrypting with it decrypts messages
+// encrypted with k. Its matrix is the inverse of k's matrix and, for affine keys, its translation
+// vector is -K^-1·b.
+func (k *Key) Inverse(mod int) (*Key, error) {
+	inverse, err := k.matrix.InverseMod(mod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invert key; %w", err)
+	}
+	inv := &Key{matrix: *inverse}
+	if k.shift != nil {
+		shift, _ := inverse.VectorProductMod(mod, k.shift...) // Neglect error since size is exact
+		for i, x := range shift {
+			shift[i] = Residue(-x, mod)
+		}
+		inv.shift = shift
+	}
+	return inv, nil
 }
 
 // NewKey initializes a Hill Cipher in an specific modulo
@@ -118,10 +321,57 @@ func NewKey(k []int, mod int) (*Key, error) {
 	}
 	m, _ := NewMatrix(int(sqr), k) // Error is neglected since order is square
 	if !m.IsInvertibleMod(mod) {
-		return nil, fmt.Errorf("key is not invertible modulo %d", mod)
+		return nil, fmt.Errorf("key %w modulo %d", ErrNotInvertible, mod)
+	}
+	return &Key{matrix: *m}, nil
+}
+
+// NewAffineKey initializes an affine Hill Cipher key in an specific modulo. The matrix k is
+// validated as in NewKey and the translation vector shift must have an entry per row.
+func NewAffineKey(k, shift []int, mod int) (*Key, error) {
+	key, err := NewKey(k, mod)
+	if err != nil {
+		return nil, err
+	}
+	if len(shift) != key.matrix.order {
+		return nil, fmt.Errorf("translation vector must have %d entries, got %d", key.matrix.order, len(shift))
+	}
+	key.shift = make([]int, len(shift))
+	for i, b := range shift {
+		key.shift[i] = Residue(b, mod)
+	}
+	return key, nil
+}
+
+// GenerateKey returns a key of the given order chosen uniformly at random among the keys that
+// are invertible modulo the alphabet size. Matrices are drawn uniformly from random until an
+// invertible one is found, which keeps the distribution uniform over valid keys. If random is
+// nil, crypto/rand.Reader is used.
+func GenerateKey(order int, alphabet *Alphabet, random io.Reader) (*Key, error) {
+	mod := len(alphabet.Symbols())
+	if mod < 2 {
+		return nil, fmt.Errorf("alphabet must contain at least 2 symbols, got %d", mod)
+	}
+	if order < 2 {
+		return nil, fmt.Errorf("cannot create key of order %d < 2", order)
+	}
+	if random == nil {
+		random = crand.Reader
+	}
+	bigMod := big.NewInt(int64(mod))
+	data := make([]int, order*order)
+	for {
+		for i := range data {
+			x, err := crand.Int(random, bigMod)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read random data; %v", err)
+			}
+			data[i] = int(x.Int64())
+		}
+		if key, err := NewKey(data, mod); err == nil {
+			return key, nil
+		}
 	}
-	key := Key(*m)
-	return &key, nil
 }
 
 // Alphabet is the set of symbols valid through a cipher
@@ -131,18 +381,22 @@ type Alphabet struct {
 	intIndex    map[int]rune
 }
 
-// NewAlphabet initializes a new Hill Cipher Alphabet.
+// NewAlphabet initializes a new Hill Cipher Alphabet. Duplicate symbols are ignored, only their
+// first occurrence is kept; use ParseAlphabet to reject them instead.
 func NewAlphabet(s string) *Alphabet {
-	symbols := []rune(s)
-	n := len(symbols)
+	n := len([]rune(s))
 	a := &Alphabet{
-		symbols:     symbols,
+		symbols:     make([]rune, 0, n),
 		intIndex:    make(map[int]rune, n),
 		symbolIndex: make(map[rune]int, n),
 	}
-	for i, s := range symbols {
-		a.intIndex[i] = s
-		a.symbolIndex[s] = i
+	for _, r := range s {
+		if a.Contains(r) {
+			continue
+		}
+		a.intIndex[len(a.symbols)] = r
+		a.symbolIndex[r] = len(a.symbols)
+		a.symbols = append(a.symbols, r)
 	}
 	return a
 }
@@ -163,10 +417,11 @@ func (a *Alphabet) Contains(r rune) bool {
 	return found
 }
 
-// Stoi returns the int value of the given symbol s (Symbol To Int).
+// Stoi returns the int value of the given symbol s (Symbol To Int). Returns a *SymbolError (at
+// position 0, since s is a single symbol) if s doesn't belong to the alphabet.
 func (a *Alphabet) Stoi(s rune) (int, error) {
 	if !a.Contains(s) {
-		return -1, fmt.Errorf("symbols %q is not part of the alphabet", s)
+		return -1, &SymbolError{Symbol: s}
 	}
 	return a.symbolIndex[s], nil
 }
@@ -180,6 +435,24 @@ func (a *Alphabet) Itos(i int) (rune, error) {
 	return r, nil
 }
 
+// KeyString returns the string representation of k in the alphabet, that is, the string that
+// would produce k when used as key in a cipher over this alphabet. The translation vector of
+// affine keys is written after the matrix entries.
+func (a *Alphabet) KeyString(k *Key) (string, error) {
+	var b strings.Builder
+	rows := append([][]int{}, k.matrix.data...)
+	for _, row := range append(rows, k.shift) {
+		for _, x := range row {
+			r, err := a.Itos(x)
+			if err != nil {
+				return "", fmt.Errorf("key entry cannot be represented in alphabet %q; %v", a, err)
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
 // Belongs returns whether a string belongs to the alphabet or not.
 func (a *Alphabet) Belongs(s string) bool {
 	for _, r := range []rune(s) {
@@ -1,7 +1,11 @@
 package cipher
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -18,18 +22,20 @@ func TestNewKey(t *testing.T) {
 		{
 			name: "order 2 mod 2 1011",
 			mod:  2, data: []int{1, 0, 1, 1},
-			wantKey: &Key{order: 2, data: [][]int{{1, 0}, {1, 1}}},
+			wantKey: &Key{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {1, 1}}}},
 		},
 		{
 			name: "order 3 mod 27 FORTALEZA",
 			mod:  27,
 			data: []int{5, 15, 18, 20, 0, 11, 4, 26, 0},
 			wantKey: &Key{
-				order: 3,
-				data: [][]int{
-					{5, 15, 18},
-					{20, 0, 11},
-					{4, 26, 0},
+				matrix: Matrix{
+					order: 3,
+					data: [][]int{
+						{5, 15, 18},
+						{20, 0, 11},
+						{4, 26, 0},
+					},
 				},
 			},
 		},
@@ -38,12 +44,14 @@ func TestNewKey(t *testing.T) {
 			mod:  27,
 			data: []int{21, 13, 0, 12, 5, 2, 8, 4, 13, 2, 8, 0, 19, 2, 25, 19},
 			wantKey: &Key{
-				order: 4,
-				data: [][]int{
-					{21, 13, 0, 12},
-					{5, 2, 8, 4},
-					{13, 2, 8, 0},
-					{19, 2, 25, 19},
+				matrix: Matrix{
+					order: 4,
+					data: [][]int{
+						{21, 13, 0, 12},
+						{5, 2, 8, 4},
+						{13, 2, 8, 0},
+						{19, 2, 25, 19},
+					},
 				},
 			},
 		},
@@ -52,18 +60,20 @@ func TestNewKey(t *testing.T) {
 			mod:  27,
 			data: []int{14, 15, 12, 4, 6, 21, 19, 20, 0, 11, 2, 15, 18, 15, 13, 0, 22, 8, 18, 21, 19, 7, 4, 11, 16},
 			wantKey: &Key{
-				order: 5,
-				data: [][]int{
-					{14, 15, 12, 4, 6},
-					{21, 19, 20, 0, 11},
-					{2, 15, 18, 15, 13},
-					{0, 22, 8, 18, 21},
-					{19, 7, 4, 11, 16},
+				matrix: Matrix{
+					order: 5,
+					data: [][]int{
+						{14, 15, 12, 4, 6},
+						{21, 19, 20, 0, 11},
+						{2, 15, 18, 15, 13},
+						{0, 22, 8, 18, 21},
+						{19, 7, 4, 11, 16},
+					},
 				},
 			},
 		},
 	}
-	unxOpt := cmp.AllowUnexported(Key{})
+	unxOpt := cmp.AllowUnexported(Key{}, Matrix{})
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			gotKey, err := NewKey(test.data, test.mod)
@@ -103,6 +113,37 @@ func TestNewKey_Error(t *testing.T) {
 	}
 }
 
+// TestNewAffineKey verify affine keys keep the translation vector reduced mod n
+func TestNewAffineKey(t *testing.T) {
+	gotKey, err := NewAffineKey([]int{1, 0, 1, 1}, []int{-1, 28}, 27)
+	if err != nil {
+		t.Fatalf("NewAffineKey() returned unexpected error; %v", err)
+	}
+	wantKey := &Key{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {1, 1}}}, shift: []int{26, 1}}
+	if diff := cmp.Diff(wantKey, gotKey, cmp.AllowUnexported(Key{}, Matrix{})); diff != "" {
+		t.Errorf("NewAffineKey()=\n%s, want \n%s: diff want -> got\n%s", gotKey, wantKey, diff)
+	}
+}
+
+// TestNewAffineKey_Error verify validations are applied
+func TestNewAffineKey_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		data, shift []int
+	}{
+		{name: "non-invertible matrix", data: []int{3, 0, 0, 1}, shift: []int{1, 2}},
+		{name: "short translation vector", data: []int{1, 0, 0, 1}, shift: []int{1}},
+		{name: "missing translation vector", data: []int{1, 0, 0, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewAffineKey(test.data, test.shift, 27); err == nil {
+				t.Fatalf("NewAffineKey(%v, %v, 27) returned nil error, want non-nil", test.data, test.shift)
+			}
+		})
+	}
+}
+
 // TestKeyString verify Key mirrors matrix string
 func TestKeyString(t *testing.T) {
 	tests := []struct {
@@ -113,15 +154,22 @@ func TestKeyString(t *testing.T) {
 		{
 			name: "order 3",
 			key: &Key{
-				order: 3,
-				data: [][]int{
-					{1, 2, 3},
-					{4, 5, 6},
-					{7, 8, 9},
+				matrix: Matrix{
+					order: 3,
+					data: [][]int{
+						{1, 2, 3},
+						{4, 5, 6},
+						{7, 8, 9},
+					},
 				},
 			},
 			wantRep: "|	1	|	2	|	3	|\n|	4	|	5	|	6	|\n|	7	|	8	|	9	|\n",
 		},
+		{
+			name:    "affine order 2",
+			key:     &Key{matrix: Matrix{order: 2, data: [][]int{{1, 2}, {3, 4}}}, shift: []int{5, 6}},
+			wantRep: "|	1	|	2	|\n|	3	|	4	|\n+|	5	|	6	|\n",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -133,6 +181,162 @@ func TestKeyString(t *testing.T) {
 	}
 }
 
+// TestKeyAccessors verify the matrix and translation vector of keys are copies
+func TestKeyAccessors(t *testing.T) {
+	key, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	m, shift := key.Matrix(), key.Shift()
+	if diff := cmp.Diff(&key.matrix, m, cmp.AllowUnexported(Matrix{})); diff != "" {
+		t.Errorf("Matrix() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(key.shift, shift); diff != "" {
+		t.Errorf("Shift() mismatch (-want +got):\n%s", diff)
+	}
+	m.data[0][0], shift[0] = -1, -1
+	if key.matrix.data[0][0] != 8 || key.shift[0] != 1 {
+		t.Errorf("modifying the result of Matrix or Shift changed the key to\n%s", key)
+	}
+	if linear := (&Key{matrix: key.matrix}); linear.Shift() != nil {
+		t.Errorf("Shift() = %v for linear key, want nil", linear.Shift())
+	}
+}
+
+// TestKeyInverse verify encrypting with the inverse key decrypts
+func TestKeyInverse(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"))
+	linear, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	affine, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	tests := []struct {
+		name    string
+		key     *Key
+		wantKey *Key
+	}{
+		{
+			name:    "linear",
+			key:     linear,
+			wantKey: &Key{matrix: Matrix{order: 2, data: [][]int{{1, 5}, {2, 23}}}},
+		},
+		{
+			name:    "affine",
+			key:     affine,
+			wantKey: &Key{matrix: Matrix{order: 2, data: [][]int{{1, 5}, {2, 23}}}, shift: []int{16, 6}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotKey, err := test.key.Inverse(27)
+			if err != nil {
+				t.Fatalf("Inverse(27) returned unexpected error; %v", err)
+			}
+			if diff := cmp.Diff(test.wantKey, gotKey, cmp.AllowUnexported(Key{}, Matrix{})); diff != "" {
+				t.Fatalf("Inverse(27) mismatch (-want +got):\n%s", diff)
+			}
+			cipherText, _ := cipher.EncryptWithKey("HOLAMUNDOS", test.key)
+			plainText, _ := cipher.EncryptWithKey(cipherText, gotKey)
+			if plainText != "HOLAMUNDOS" {
+				t.Errorf("encrypting %q with the inverse key = %q, want %q", cipherText, plainText, "HOLAMUNDOS")
+			}
+		})
+	}
+}
+
+// TestKeyInverse_Error verify singular keys can't be inverted
+func TestKeyInverse_Error(t *testing.T) {
+	key := &Key{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {0, 3}}}}
+	if _, err := key.Inverse(27); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("Inverse(27) = %v, want %v", err, ErrNotInvertible)
+	}
+}
+
+// TestGenerateKey verify generated keys are valid for the alphabet
+func TestGenerateKey(t *testing.T) {
+	tests := []struct {
+		name, alphabet string
+		order          int
+		defaultSource  bool // Whether to use a nil source, i.e. crypto/rand
+	}{
+		{name: "binary order 2", alphabet: "01", order: 2},
+		{name: "english order 3", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", order: 3},
+		{name: "english order 3 default source", alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", order: 3, defaultSource: true},
+		{name: "spanish order 10", alphabet: "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ", order: 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alphabet := NewAlphabet(test.alphabet)
+			mod := len(alphabet.Symbols())
+			var random io.Reader
+			if !test.defaultSource {
+				random = rand.New(rand.NewSource(int64(test.order)))
+			}
+			key, err := GenerateKey(test.order, alphabet, random)
+			if err != nil {
+				t.Fatalf("GenerateKey(%d, %q) returned unexpected error; %v", test.order, alphabet, err)
+			}
+			if key.matrix.order != test.order {
+				t.Errorf("GenerateKey(%d, %q) returned key of order %d", test.order, alphabet, key.matrix.order)
+			}
+			m := key.matrix
+			if !m.IsInvertibleMod(mod) {
+				t.Errorf("GenerateKey(%d, %q) =\n%s, not invertible mod %d", test.order, alphabet, key, mod)
+			}
+			rawKey, err := alphabet.KeyString(key)
+			if err != nil {
+				t.Fatalf("KeyString(\n%s) returned unexpected error; %v", key, err)
+			}
+			cipher, _ := NewCipher(alphabet)
+			msg := strings.Repeat(string(alphabet.Symbols()[1]), test.order)
+			cipherText, err := cipher.Encrypt(msg, rawKey)
+			if err != nil {
+				t.Fatalf("Encrypt(msg:%q, key:%q) returned unexpected error; %v", msg, rawKey, err)
+			}
+			if plainText, _ := cipher.Decrypt(cipherText, rawKey); plainText != msg {
+				t.Errorf("Decrypt(Encrypt(%q)) = %q using generated key %q", msg, plainText, rawKey)
+			}
+		})
+	}
+}
+
+// TestGenerateKey_Error verify validations are applied
+func TestGenerateKey_Error(t *testing.T) {
+	tests := []struct {
+		name, alphabet string
+		order          int
+		random         io.Reader
+	}{
+		{name: "alphabet with one symbol", alphabet: "A", order: 2, random: rand.New(rand.NewSource(1))},
+		{name: "order 1", alphabet: "01", order: 1, random: rand.New(rand.NewSource(1))},
+		{name: "exhausted random source", alphabet: "01", order: 2, random: strings.NewReader("")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := GenerateKey(test.order, NewAlphabet(test.alphabet), test.random); err == nil {
+				t.Fatalf("GenerateKey(%d, %q) returned nil error, want non-nil", test.order, test.alphabet)
+			}
+		})
+	}
+}
+
+// TestAlphabetKeyString verify affine keys are written with their translation vector
+func TestAlphabetKeyString(t *testing.T) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	key, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	if got, err := alphabet.KeyString(key); got != "IKEYBC" || err != nil {
+		t.Errorf("KeyString(\n%s) = %q, %v; want \"IKEYBC\", nil", key, got, err)
+	}
+}
+
+// TestKeyString_Error verify keys with entries outside the alphabet are rejected
+func TestKeyString_Error(t *testing.T) {
+	keys := []*Key{
+		{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {2, 1}}}},
+		{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {1, 1}}}, shift: []int{0, 2}},
+	}
+	for _, key := range keys {
+		if _, err := NewAlphabet("01").KeyString(key); err == nil {
+			t.Errorf("KeyString(\n%s) returned nil error, want non-nil", key)
+		}
+	}
+}
+
 // TestNewAlphabet verifies correct initialization of alphaber
 func TestNewAlphabet(t *testing.T) {
 	tests := []struct {
@@ -166,6 +370,15 @@ func TestNewAlphabet(t *testing.T) {
 				symbolIndex: map[rune]int{'A': 0, 'B': 1, 'C': 2, 'D': 3, 'E': 4, 'F': 5, 'G': 6, 'H': 7, 'I': 8, 'J': 9, 'K': 10, 'L': 11, 'M': 12, 'N': 13, 'Ñ': 14, 'O': 15, 'P': 16, 'Q': 17, 'R': 18, 'S': 19, 'T': 20, 'U': 21, 'V': 22, 'W': 23, 'X': 24, 'Y': 25, 'Z': 26},
 			},
 		},
+		{
+			name: "duplicate symbols keep first occurrence",
+			data: "ABAC",
+			wantAlphabet: &Alphabet{
+				symbols:     []rune{'A', 'B', 'C'},
+				intIndex:    map[int]rune{0: 'A', 1: 'B', 2: 'C'},
+				symbolIndex: map[rune]int{'A': 0, 'B': 1, 'C': 2},
+			},
+		},
 	}
 	unxOpt := cmp.AllowUnexported(Alphabet{})
 	for _, test := range tests {
@@ -385,7 +598,7 @@ func TestNewCipher(t *testing.T) {
 				t.Fatalf("NewCipher(%s) returned unexpected error; %v", test.alphabet, err)
 			}
 			if diff := cmp.Diff(test.wantCipher, gotCipher, unxOpt); diff != "" {
-				t.Errorf("NewCipher(%s) = %v, want %d; diff want -> got %s", test.alphabet, gotCipher, test.wantCipher, diff)
+				t.Errorf("NewCipher(%s) = %v, want %v; diff want -> got %s", test.alphabet, gotCipher, test.wantCipher, diff)
 			}
 		})
 	}
@@ -424,6 +637,13 @@ func TestEncryption(t *testing.T) {
 			key:            "FORTALEZA",
 			wantCipherText: "KUTÑOB",
 		},
+		{
+			name:           "spanish alphabet affine key size 3",
+			alphabet:       "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			msg:            "CONSUL",
+			key:            "FORTALEZABCD",
+			wantCipherText: "LWWOQE",
+		},
 		{
 			name:           "spanish alphabet key size 4",
 			alphabet:       "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
@@ -478,6 +698,7 @@ func TestEncryption_Error(t *testing.T) {
 			name:     "message does not belong to alphabet",
 			alphabet: "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
 			msg:      "sup",
+			key:      "FORTALEZA",
 		},
 		{
 			name:     "message length is not multiple of key's length",
@@ -523,6 +744,13 @@ func TestDecryption(t *testing.T) {
 			key:           "FORTALEZA",
 			cipherText:    "KUTÑOB",
 		},
+		{
+			name:          "spanish alphabet affine key size 3",
+			alphabet:      "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			wantPlainText: "CONSUL",
+			key:           "FORTALEZABCD",
+			cipherText:    "LWWOQE",
+		},
 		{
 			name:          "spanish alphabet key size 4",
 			alphabet:      "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
@@ -577,6 +805,7 @@ func TestDecryption_Error(t *testing.T) {
 			name:       "cipher text does not belong to alphabet",
 			alphabet:   "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
 			cipherText: "sup",
+			key:        "FORTALEZA",
 		},
 		{
 			name:       "cipher text length is not multiple of key's length",
@@ -609,3 +838,139 @@ func TestDecryption_Error(t *testing.T) {
 		})
 	}
 }
+
+// TestCipherWithKey verify encryption and decryption using pre-built numeric keys
+func TestCipherWithKey(t *testing.T) {
+	english := NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	key, _ := NewKey([]int{3, 3, 2, 5}, 26)
+	affineKey, _ := NewAffineKey([]int{3, 3, 2, 5}, []int{1, 2}, 26)
+	tests := []struct {
+		name, msg, wantCipherText string
+		key                       *Key
+	}{
+		{name: "textbook key", msg: "HELP", key: key, wantCipherText: "HIAT"},
+		{name: "affine key", msg: "HELP", key: affineKey, wantCipherText: "IKBV"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(english)
+			gotCipherText, err := cipher.EncryptWithKey(test.msg, test.key)
+			if err != nil {
+				t.Fatalf("EncryptWithKey(msg:%q, key:\n%s) returned unexpected error; %v", test.msg, test.key, err)
+			}
+			if gotCipherText != test.wantCipherText {
+				t.Errorf("EncryptWithKey(msg:%q, key:\n%s) = %q, want %q", test.msg, test.key, gotCipherText, test.wantCipherText)
+			}
+			gotPlainText, err := cipher.DecryptWithKey(gotCipherText, test.key)
+			if err != nil {
+				t.Fatalf("DecryptWithKey(msg:%q, key:\n%s) returned unexpected error; %v", gotCipherText, test.key, err)
+			}
+			if gotPlainText != test.msg {
+				t.Errorf("DecryptWithKey(msg:%q, key:\n%s) = %q, want %q", gotCipherText, test.key, gotPlainText, test.msg)
+			}
+		})
+	}
+}
+
+// TestCipherWithKey_Error verify keys built for another modulo are rejected
+func TestCipherWithKey_Error(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	singularKey, _ := NewKey([]int{1, 0, 0, 2}, 27) // Determinant 2 is not invertible mod 26
+	outOfRangeKey, _ := NewKey([]int{1, 0, 0, 26}, 27)
+	for _, key := range []*Key{singularKey, outOfRangeKey} {
+		if _, err := cipher.EncryptWithKey("HELP", key); err == nil {
+			t.Errorf("EncryptWithKey(key:\n%s) returned nil error, want non-nil", key)
+		}
+		if _, err := cipher.DecryptWithKey("HELP", key); err == nil {
+			t.Errorf("DecryptWithKey(key:\n%s) returned nil error, want non-nil", key)
+		}
+	}
+}
+
+// benchmarkSetup returns a cipher, an order 10 key and a bulk message (and its encryption) of
+// 100,000 symbols.
+func benchmarkSetup(b *testing.B) (*Cipher, string, string, string) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	cipher, _ := NewCipher(alphabet)
+	key, err := GenerateKey(10, alphabet, rand.New(rand.NewSource(1)))
+	if err != nil {
+		b.Fatalf("GenerateKey() returned unexpected error; %v", err)
+	}
+	rawKey, _ := alphabet.KeyString(key)
+	msg := strings.Repeat("LYRICALLYPERFORMARMEDROBBERYFLEEWITHLOTTERYPOSSIBLYTHEY", 2000)[:100000]
+	cipherText, _ := cipher.Encrypt(msg, rawKey)
+	return cipher, rawKey, msg, cipherText
+}
+
+// BenchmarkEncrypt measures bulk encryption parsing the key on every call
+func BenchmarkEncrypt(b *testing.B) {
+	cipher, rawKey, msg, _ := benchmarkSetup(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cipher.Encrypt(msg, rawKey)
+	}
+}
+
+// BenchmarkSessionEncrypt measures bulk encryption using a session
+func BenchmarkSessionEncrypt(b *testing.B) {
+	cipher, rawKey, msg, _ := benchmarkSetup(b)
+	key, _ := cipher.ParseKey(rawKey)
+	session, _ := cipher.NewSession(key)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session.Encrypt(msg)
+	}
+}
+
+// BenchmarkDecrypt measures bulk decryption parsing and inverting the key on every call
+func BenchmarkDecrypt(b *testing.B) {
+	cipher, rawKey, _, cipherText := benchmarkSetup(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cipher.Decrypt(cipherText, rawKey)
+	}
+}
+
+// BenchmarkSessionDecrypt measures bulk decryption using a session
+func BenchmarkSessionDecrypt(b *testing.B) {
+	cipher, rawKey, _, cipherText := benchmarkSetup(b)
+	key, _ := cipher.ParseKey(rawKey)
+	session, _ := cipher.NewSession(key)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session.Decrypt(cipherText)
+	}
+}
+
+// BenchmarkSessionEncrypt_Padding measures bulk encryption using a session of a cipher with
+// padding, which can't use the single pass transformation
+func BenchmarkSessionEncrypt_Padding(b *testing.B) {
+	_, rawKey, msg, _ := benchmarkSetup(b)
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"), WithPadding(LengthPadding{}))
+	key, _ := cipher.ParseKey(rawKey)
+	session, _ := cipher.NewSession(key)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session.Encrypt(msg)
+	}
+}
+
+// BenchmarkSessionDecrypt_Padding measures bulk decryption using a session of a cipher with
+// padding, which can't use the single pass transformation
+func BenchmarkSessionDecrypt_Padding(b *testing.B) {
+	_, rawKey, msg, _ := benchmarkSetup(b)
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"), WithPadding(LengthPadding{}))
+	key, _ := cipher.ParseKey(rawKey)
+	session, _ := cipher.NewSession(key)
+	cipherText, _ := session.Encrypt(msg)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session.Decrypt(cipherText)
+	}
+}
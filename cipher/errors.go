@@ -0,0 +1,51 @@
+package cipher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the cipher, possibly wrapped with more context. Use errors.Is to test them.
+var (
+	// ErrNotInvertible is returned when a key matrix is not invertible modulo the alphabet size.
+	ErrNotInvertible = errors.New("matrix is not invertible")
+	// ErrSymbolNotInAlphabet is returned when a message or key has a symbol that doesn't belong to
+	// the alphabet. The error is a *SymbolError, use errors.As to get the symbol and its position.
+	ErrSymbolNotInAlphabet = errors.New("symbol does not belong to alphabet")
+	// ErrLengthNotMultiple is returned when the length of a message is not multiple of the key's
+	// order and it can't be padded.
+	ErrLengthNotMultiple = errors.New("message length is not multiple of key's order")
+	// ErrNoSolution is returned when a linear system has no solution.
+	ErrNoSolution = errors.New("system has no solution")
+)
+
+// SymbolError reports a symbol that doesn't belong to the alphabet. It matches
+// ErrSymbolNotInAlphabet through errors.Is.
+type SymbolError struct {
+	// Symbol is the unknown symbol.
+	Symbol rune
+	// Position is the index of the symbol in the text, counted in symbols (runes) from 0.
+	Position int
+}
+
+func (e *SymbolError) Error() string {
+	return fmt.Sprintf("symbol %q at position %d does not belong to alphabet", e.Symbol, e.Position)
+}
+
+// Is makes SymbolError match ErrSymbolNotInAlphabet.
+func (e *SymbolError) Is(target error) bool {
+	return target == ErrSymbolNotInAlphabet
+}
+
+// unknownSymbol returns the error for the first symbol of s that doesn't belong to the alphabet,
+// or nil if every symbol does.
+func (a *Alphabet) unknownSymbol(s string) error {
+	pos := 0
+	for _, r := range s {
+		if !a.Contains(r) {
+			return &SymbolError{Symbol: r, Position: pos}
+		}
+		pos++
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package cipher
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// TestErrors verify validation errors can be tested with errors.Is and errors.As
+func TestErrors(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	cipher, _ := NewCipher(spanish)
+	normalized, _ := NewCipher(spanish, WithNormalizer(Normalizer{FoldCase: true}))
+	// NFC maps the combining dialytika tonos into dialytika followed by acute accent
+	expanded, _ := NewCipher(NewAlphabet("AB\u0308"), WithNormalizer(Normalizer{}))
+	cbc, _ := NewCipher(spanish, WithMode(CBC{IV: []int{1, 2}}))
+	key, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	singularKey := &Key{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {0, 3}}}}
+	session, _ := cipher.NewSession(key)
+	tests := []struct {
+		name    string
+		op      func() error
+		wantErr error
+		wantPos int // Position of the unknown symbol, only if wantErr is ErrSymbolNotInAlphabet
+	}{
+		{
+			name:    "new key not invertible",
+			op:      func() error { _, err := NewKey([]int{1, 0, 0, 3}, 27); return err },
+			wantErr: ErrNotInvertible,
+		},
+		{
+			name:    "parsed key not invertible",
+			op:      func() error { _, err := cipher.Encrypt("HOLA", "AAAA"); return err },
+			wantErr: ErrNotInvertible,
+		},
+		{
+			name:    "key not invertible",
+			op:      func() error { _, err := cipher.EncryptWithKey("HOLA", singularKey); return err },
+			wantErr: ErrNotInvertible,
+		},
+		{
+			name:    "matrix not invertible",
+			op:      func() error { _, err := singularKey.matrix.InverseMod(27); return err },
+			wantErr: ErrNotInvertible,
+		},
+		{
+			name:    "key symbol not in alphabet",
+			op:      func() error { _, err := cipher.Encrypt("HOLA", "IKeY"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 2,
+		},
+		{
+			name:    "message symbol not in alphabet",
+			op:      func() error { _, err := cipher.Encrypt("HÓLA", "IKEY"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 1,
+		},
+		{
+			name:    "cipher text symbol not in alphabet",
+			op:      func() error { _, err := cipher.Decrypt("QYH?", "IKEY"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 3,
+		},
+		{
+			name:    "normalized symbol not in alphabet",
+			op:      func() error { _, err := normalized.Encrypt("hola mundo", "IKEY"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 4,
+		},
+		{
+			name:    "alphabet symbol not in alphabet",
+			op:      func() error { _, err := spanish.Stoi('?'); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+		},
+		{
+			name:    "length padding symbol not in alphabet",
+			op:      func() error { _, err := LengthPadding{}.Unpad([]rune("AB?"), 3, spanish); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 2,
+		},
+		{
+			name:    "random padding symbol not in alphabet",
+			op:      func() error { _, err := RandomPadding{}.Unpad([]rune("?AB"), 3, spanish); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+		},
+		{
+			name:    "decomposed symbol not in alphabet",
+			op:      func() error { _, err := normalized.Encrypt("N\u0303o?", "IKEY"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 3,
+		},
+		{
+			name:    "expanded symbol not in alphabet",
+			op:      func() error { _, err := expanded.Encrypt("B\u0344A", "BAAB"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 1,
+		},
+		{
+			name:    "session symbol not in alphabet",
+			op:      func() error { _, err := session.Encrypt("HOLA!"); return err },
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 4,
+		},
+		{
+			name: "stream symbol not in alphabet",
+			op: func() error {
+				w, _ := cipher.NewEncryptWriter(ioutil.Discard, "IKEY")
+				w.Write([]byte("HOLA"))
+				_, err := w.Write([]byte("MU-NDO"))
+				return err
+			},
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 6,
+		},
+		{
+			name: "stream cipher text symbol not in alphabet",
+			op: func() error {
+				r, _ := cipher.NewDecryptReader(strings.NewReader("QYHQ?"), "IKEY")
+				_, err := ioutil.ReadAll(r)
+				return err
+			},
+			wantErr: ErrSymbolNotInAlphabet,
+			wantPos: 4,
+		},
+		{
+			name:    "message length not multiple",
+			op:      func() error { _, err := cipher.Encrypt("SOL", "IKEY"); return err },
+			wantErr: ErrLengthNotMultiple,
+		},
+		{
+			name:    "session message length not multiple",
+			op:      func() error { _, err := session.Decrypt("SOL"); return err },
+			wantErr: ErrLengthNotMultiple,
+		},
+		{
+			name: "stream message length not multiple",
+			op: func() error {
+				w, _ := cipher.NewEncryptWriter(ioutil.Discard, "IKEY")
+				w.Write([]byte("SOL"))
+				return w.Close()
+			},
+			wantErr: ErrLengthNotMultiple,
+		},
+		{
+			name: "stream cipher text length not multiple",
+			op: func() error {
+				r, _ := cipher.NewDecryptReader(strings.NewReader("SOL"), "IKEY")
+				_, err := ioutil.ReadAll(r)
+				return err
+			},
+			wantErr: ErrLengthNotMultiple,
+		},
+		{
+			name:    "mode key not invertible",
+			op:      func() error { _, err := cbc.decrypter(singularKey, nil); return err },
+			wantErr: ErrNotInvertible,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.op()
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			var symbolErr *SymbolError
+			wantSymbolErr := test.wantErr == ErrSymbolNotInAlphabet
+			if got := errors.As(err, &symbolErr); got != wantSymbolErr {
+				t.Fatalf("errors.As(%v, *SymbolError) = %t, want %t", err, got, wantSymbolErr)
+			}
+			if symbolErr != nil && symbolErr.Position != test.wantPos {
+				t.Errorf("got unknown symbol %q at position %d, want position %d", symbolErr.Symbol, symbolErr.Position, test.wantPos)
+			}
+		})
+	}
+}
@@ -2,4 +2,7 @@ module github.com/pablotrinidad/hillcipher/cipher
 
 go 1.14
 
-require github.com/google/go-cmp v0.4.0
+require (
+	github.com/google/go-cmp v0.4.0
+	golang.org/x/text v0.3.3
+)
@@ -0,0 +1,302 @@
+package cipher
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// armorVersion is the version of the armored key format written by ArmorKey.
+	armorVersion = 1
+	// armorBegin and armorEnd delimit armored keys.
+	armorBegin = "-----BEGIN HILL KEY-----"
+	armorEnd   = "-----END HILL KEY-----"
+	// binaryVersion is the version of the binary key format written by MarshalBinary.
+	binaryVersion = 1
+)
+
+// keyJSON is the JSON representation of a Key.
+type keyJSON struct {
+	Matrix [][]int `json:"matrix"`
+	Shift  []int   `json:"shift,omitempty"`
+}
+
+// newKeyFromRows returns the key with the given rows and translation vector (nil if the key isn't
+// affine). Only the shape of the key is verified, since invertibility depends on the modulo.
+func newKeyFromRows(rows [][]int, shift []int) (*Key, error) {
+	order := len(rows)
+	if order < 2 {
+		return nil, fmt.Errorf("cannot create key of order %d < 2", order)
+	}
+	data := make([]int, 0, order*order)
+	for i, row := range rows {
+		if len(row) != order {
+			return nil, fmt.Errorf("key row %d has %d entries, want %d", i, len(row), order)
+		}
+		data = append(data, row...)
+	}
+	for _, x := range append(data, shift...) {
+		if x < 0 {
+			return nil, fmt.Errorf("key entries must be non-negative, got %d", x)
+		}
+	}
+	if shift != nil && len(shift) != order {
+		return nil, fmt.Errorf("translation vector must have %d entries, got %d", order, len(shift))
+	}
+	m, _ := NewMatrix(order, data) // Neglect error since data has order^2 entries
+	return &Key{matrix: *m, shift: shift}, nil
+}
+
+// MarshalJSON implements json.Marshaler. Keys are encoded as an object with the matrix rows and,
+// for affine keys, the translation vector, e.g. {"matrix":[[3,3],[2,5]],"shift":[1,2]}.
+func (k Key) MarshalJSON() ([]byte, error) {
+	return json.Marshal(keyJSON{Matrix: k.matrix.data, Shift: k.shift})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var raw keyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode key; %v", err)
+	}
+	key, err := newKeyFromRows(raw.Matrix, raw.Shift)
+	if err != nil {
+		return fmt.Errorf("failed to decode key; %v", err)
+	}
+	*k = *key
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. Keys are encoded as a matrix literal followed,
+// for affine keys, by the translation vector, e.g. [[3,3],[2,5]]+[1,2].
+func (k Key) MarshalText() ([]byte, error) {
+	text, _ := json.Marshal(k.matrix.data) // Neglect error since ints are always encodable
+	if k.shift != nil {
+		shift, _ := json.Marshal(k.shift) // Neglect error since ints are always encodable
+		text = append(append(text, '+'), shift...)
+	}
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *Key) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "+", 2)
+	var rows [][]int
+	if err := json.Unmarshal([]byte(parts[0]), &rows); err != nil {
+		return fmt.Errorf("got invalid matrix literal %q; %v", parts[0], err)
+	}
+	var shift []int
+	if len(parts) == 2 {
+		if err := json.Unmarshal([]byte(parts[1]), &shift); err != nil || shift == nil {
+			return fmt.Errorf("got invalid translation vector %q", parts[1])
+		}
+	}
+	key, err := newKeyFromRows(rows, shift)
+	if err != nil {
+		return fmt.Errorf("failed to decode key; %v", err)
+	}
+	*k = *key
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is the format version, whether
+// the key is affine (0 or 1), the order and the entries of the matrix and translation vector, all
+// of them as unsigned varints.
+func (k Key) MarshalBinary() ([]byte, error) {
+	values := []int{binaryVersion, 0, k.matrix.order}
+	if k.shift != nil {
+		values[1] = 1
+	}
+	for _, row := range k.matrix.data {
+		values = append(values, row...)
+	}
+	values = append(values, k.shift...)
+	data := make([]byte, 0, len(values))
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, x := range values {
+		data = append(data, buf[:binary.PutUvarint(buf, uint64(x))]...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (k *Key) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	next := func() (int, error) {
+		x, err := binary.ReadUvarint(r)
+		if err != nil || x > uint64(^uint(0)>>1) {
+			return 0, fmt.Errorf("truncated or invalid binary key")
+		}
+		return int(x), nil
+	}
+	var header [3]int
+	for i := range header {
+		x, err := next()
+		if err != nil {
+			return err
+		}
+		header[i] = x
+	}
+	version, affine, order := header[0], header[1], header[2]
+	if version != binaryVersion {
+		return fmt.Errorf("unsupported binary key version %d", version)
+	}
+	if affine > 1 || order > r.Len() {
+		return fmt.Errorf("truncated or invalid binary key")
+	}
+	rows := make([][]int, order)
+	for i := range rows {
+		rows[i] = make([]int, order)
+		for j := range rows[i] {
+			x, err := next()
+			if err != nil {
+				return err
+			}
+			rows[i][j] = x
+		}
+	}
+	var shift []int
+	for i := 0; i < affine*order; i++ {
+		x, err := next()
+		if err != nil {
+			return err
+		}
+		shift = append(shift, x)
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("got %d unexpected trailing bytes in binary key", r.Len())
+	}
+	key, err := newKeyFromRows(rows, shift)
+	if err != nil {
+		return fmt.Errorf("failed to decode key; %v", err)
+	}
+	*k = *key
+	return nil
+}
+
+// Fingerprint returns a short identifier of the alphabet's symbols and their order, the first 8
+// bytes of the SHA-256 digest of the alphabet, in hexadecimal.
+func (a *Alphabet) Fingerprint() string {
+	sum := sha256.Sum256([]byte(string(a.symbols)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// formatInts returns the ints separated by spaces.
+func formatInts(ints []int) string {
+	s := make([]string, len(ints))
+	for i, x := range ints {
+		s[i] = strconv.Itoa(x)
+	}
+	return strings.Join(s, " ")
+}
+
+// parseInts returns the ints of a line separated by spaces.
+func parseInts(line string) ([]int, error) {
+	fields := strings.Fields(line)
+	ints := make([]int, len(fields))
+	for i, f := range fields {
+		x, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid number %q", f)
+		}
+		ints[i] = x
+	}
+	return ints, nil
+}
+
+// ArmorKey returns key in the versioned armored text format, which records the key's order, the
+// cipher's modulo and alphabet fingerprint, the translation vector of affine keys and the matrix
+// rows. Returns an error if key is not invertible by cipher's modulo.
+func (c *Cipher) ArmorKey(key *Key) ([]byte, error) {
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintln(&b, armorBegin)
+	fmt.Fprintf(&b, "Version: %d\n", armorVersion)
+	fmt.Fprintf(&b, "Order: %d\n", key.matrix.order)
+	fmt.Fprintf(&b, "Modulus: %d\n", c.mod)
+	fmt.Fprintf(&b, "Alphabet: %s\n", c.alphabet.Fingerprint())
+	if key.shift != nil {
+		fmt.Fprintf(&b, "Shift: %s\n", formatInts(key.shift))
+	}
+	fmt.Fprintln(&b)
+	for _, row := range key.matrix.data {
+		fmt.Fprintln(&b, formatInts(row))
+	}
+	fmt.Fprintln(&b, armorEnd)
+	return b.Bytes(), nil
+}
+
+// ParseArmoredKey returns the key encoded in data by ArmorKey. Returns an error if the format is
+// invalid or if the key wasn't created for a cipher with the same modulo and alphabet.
+func (c *Cipher) ParseArmoredKey(data []byte) (*Key, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimSpace(data)))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if len(lines) < 2 || lines[0] != armorBegin || lines[len(lines)-1] != armorEnd {
+		return nil, fmt.Errorf("armored key must be delimited by %q and %q", armorBegin, armorEnd)
+	}
+	lines = lines[1 : len(lines)-1]
+
+	headers := make(map[string]string)
+	for len(lines) > 0 && lines[0] != "" {
+		parts := strings.SplitN(lines[0], ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("got invalid armored key header %q", lines[0])
+		}
+		headers[parts[0]] = strings.TrimSpace(parts[1])
+		lines = lines[1:]
+	}
+	if len(lines) > 0 {
+		lines = lines[1:] // Skip blank line between headers and matrix
+	}
+	for _, h := range []string{"Version", "Order", "Modulus", "Alphabet"} {
+		if _, found := headers[h]; !found {
+			return nil, fmt.Errorf("armored key is missing the %s header", h)
+		}
+	}
+	if v := headers["Version"]; v != strconv.Itoa(armorVersion) {
+		return nil, fmt.Errorf("unsupported armored key version %s", v)
+	}
+	if m := headers["Modulus"]; m != strconv.Itoa(c.mod) {
+		return nil, fmt.Errorf("key modulus %s doesn't match cipher modulus %d", m, c.mod)
+	}
+	if f := headers["Alphabet"]; f != c.alphabet.Fingerprint() {
+		return nil, fmt.Errorf("key alphabet fingerprint %s doesn't match cipher alphabet %s", f, c.alphabet.Fingerprint())
+	}
+	order, err := strconv.Atoi(headers["Order"])
+	if err != nil || order != len(lines) {
+		return nil, fmt.Errorf("key order %s doesn't match the %d matrix rows", headers["Order"], len(lines))
+	}
+
+	rows := make([][]int, order)
+	for i, line := range lines {
+		if rows[i], err = parseInts(line); err != nil {
+			return nil, fmt.Errorf("got invalid matrix row %d; %v", i, err)
+		}
+	}
+	var shift []int
+	if s, found := headers["Shift"]; found {
+		if shift, err = parseInts(s); err != nil {
+			return nil, fmt.Errorf("got invalid translation vector; %v", err)
+		}
+	}
+	key, err := newKeyFromRows(rows, shift)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key; %v", err)
+	}
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
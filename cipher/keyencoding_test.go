@@ -0,0 +1,185 @@
+package cipher
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestKeyEncoding verify keys are encoded and decoded back in JSON, text and binary formats
+func TestKeyEncoding(t *testing.T) {
+	key, _ := NewKey([]int{3, 3, 2, 5}, 26)
+	affineKey, _ := NewAffineKey([]int{3, 3, 2, 5}, []int{1, 300}, 1000)
+	tests := []struct {
+		name               string
+		key                *Key
+		wantJSON, wantText string
+	}{
+		{name: "linear key", key: key, wantJSON: `{"matrix":[[3,3],[2,5]]}`, wantText: "[[3,3],[2,5]]"},
+		{name: "affine key", key: affineKey, wantJSON: `{"matrix":[[3,3],[2,5]],"shift":[1,300]}`, wantText: "[[3,3],[2,5]]+[1,300]"},
+	}
+	unxOpt := cmp.AllowUnexported(Key{}, Matrix{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotJSON, err := json.Marshal(test.key)
+			if err != nil {
+				t.Fatalf("json.Marshal(\n%s) returned unexpected error; %v", test.key, err)
+			}
+			if string(gotJSON) != test.wantJSON {
+				t.Errorf("json.Marshal(\n%s) = %s, want %s", test.key, gotJSON, test.wantJSON)
+			}
+			var fromJSON Key
+			if err := json.Unmarshal(gotJSON, &fromJSON); err != nil {
+				t.Fatalf("json.Unmarshal(%s) returned unexpected error; %v", gotJSON, err)
+			}
+			if diff := cmp.Diff(test.key, &fromJSON, unxOpt); diff != "" {
+				t.Errorf("json.Unmarshal(%s) diff want -> got\n%s", gotJSON, diff)
+			}
+
+			gotText, _ := test.key.MarshalText()
+			if string(gotText) != test.wantText {
+				t.Errorf("MarshalText(\n%s) = %s, want %s", test.key, gotText, test.wantText)
+			}
+			var fromText Key
+			if err := fromText.UnmarshalText(gotText); err != nil {
+				t.Fatalf("UnmarshalText(%s) returned unexpected error; %v", gotText, err)
+			}
+			if diff := cmp.Diff(test.key, &fromText, unxOpt); diff != "" {
+				t.Errorf("UnmarshalText(%s) diff want -> got\n%s", gotText, diff)
+			}
+
+			gotBinary, _ := test.key.MarshalBinary()
+			var fromBinary Key
+			if err := fromBinary.UnmarshalBinary(gotBinary); err != nil {
+				t.Fatalf("UnmarshalBinary(%v) returned unexpected error; %v", gotBinary, err)
+			}
+			if diff := cmp.Diff(test.key, &fromBinary, unxOpt); diff != "" {
+				t.Errorf("UnmarshalBinary(%v) diff want -> got\n%s", gotBinary, diff)
+			}
+		})
+	}
+}
+
+// TestKeyEncoding_Error verify invalid encoded keys are rejected
+func TestKeyEncoding_Error(t *testing.T) {
+	invalidJSON := []string{
+		`[[3,3],[2,5]]`,
+		`{"matrix":[[3,3],[2]]}`,
+		`{"matrix":[[3]]}`,
+		`{"matrix":[[3,-3],[2,5]]}`,
+		`{"matrix":[[3,3],[2,5]],"shift":[1]}`,
+	}
+	for _, data := range invalidJSON {
+		var k Key
+		if err := json.Unmarshal([]byte(data), &k); err == nil {
+			t.Errorf("json.Unmarshal(%s) returned nil error, want non-nil", data)
+		}
+	}
+
+	invalidText := []string{"", "[[3,3],[2,5]", "[[3,3],[2,5]]+", "[[3,3],[2,5]]+[1,2,3]", "[[3,3,3],[2,5,5]]"}
+	for _, text := range invalidText {
+		var k Key
+		if err := k.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%s) returned nil error, want non-nil", text)
+		}
+	}
+
+	invalidBinary := [][]byte{
+		{},
+		{2, 0, 2, 3, 3, 2, 5},             // Unsupported version
+		{1, 2, 2, 3, 3, 2, 5},             // Invalid affine flag
+		{1, 0, 2, 3, 3, 2},                // Missing entry
+		{1, 1, 2, 3, 3, 2, 5, 1},          // Missing shift entry
+		{1, 0, 2, 3, 3, 2, 5, 0},          // Trailing bytes
+		{1, 0, 1, 3},                      // Order 1
+		{1, 0, 2, 3, 3, 2, 0xff, 0xff},    // Truncated varint
+		{1, 0, 0xff, 0xff, 0xff, 0xff, 1}, // Order larger than data
+		{1, 0, 2, 3, 3, 2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1}, // Entry overflows int
+	}
+	for _, data := range invalidBinary {
+		var k Key
+		if err := k.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) returned nil error, want non-nil", data)
+		}
+	}
+}
+
+// TestAlphabetFingerprint verify fingerprints depend on symbols and their order
+func TestAlphabetFingerprint(t *testing.T) {
+	english := NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ").Fingerprint()
+	if want := "d6ec6898de87ddac"; english != want {
+		t.Errorf("Fingerprint() = %s, want %s", english, want)
+	}
+	if reversed := NewAlphabet("ZYXWVUTSRQPONMLKJIHGFEDCBA").Fingerprint(); reversed == english {
+		t.Errorf("Fingerprint() of reversed alphabet = %s, want different fingerprint", reversed)
+	}
+}
+
+// TestArmorKey verify keys are armored and parsed back
+func TestArmorKey(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	key, _ := NewAffineKey([]int{3, 3, 2, 5}, []int{1, 2}, 26)
+	want := "-----BEGIN HILL KEY-----\n" +
+		"Version: 1\n" +
+		"Order: 2\n" +
+		"Modulus: 26\n" +
+		"Alphabet: d6ec6898de87ddac\n" +
+		"Shift: 1 2\n" +
+		"\n" +
+		"3 3\n" +
+		"2 5\n" +
+		"-----END HILL KEY-----\n"
+	armored, err := cipher.ArmorKey(key)
+	if err != nil {
+		t.Fatalf("ArmorKey(\n%s) returned unexpected error; %v", key, err)
+	}
+	if string(armored) != want {
+		t.Errorf("ArmorKey(\n%s) = \n%s, want \n%s", key, armored, want)
+	}
+	got, err := cipher.ParseArmoredKey(armored)
+	if err != nil {
+		t.Fatalf("ParseArmoredKey(\n%s) returned unexpected error; %v", armored, err)
+	}
+	if diff := cmp.Diff(key, got, cmp.AllowUnexported(Key{}, Matrix{})); diff != "" {
+		t.Errorf("ParseArmoredKey(\n%s) diff want -> got\n%s", armored, diff)
+	}
+}
+
+// TestArmorKey_Error verify validations of armored keys
+func TestArmorKey_Error(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	singularKey, _ := NewKey([]int{1, 0, 0, 2}, 27)
+	if _, err := cipher.ArmorKey(singularKey); err == nil {
+		t.Errorf("ArmorKey(\n%s) returned nil error, want non-nil", singularKey)
+	}
+
+	valid := "-----BEGIN HILL KEY-----\nVersion: 1\nOrder: 2\nModulus: 26\nAlphabet: d6ec6898de87ddac\n\n3 3\n2 5\n-----END HILL KEY-----\n"
+	tests := []struct {
+		name, old, new string
+	}{
+		{name: "missing begin", old: "-----BEGIN HILL KEY-----\n"},
+		{name: "missing end", old: "-----END HILL KEY-----\n"},
+		{name: "invalid header", old: "Version: 1", new: "Version 1"},
+		{name: "missing header", old: "Order: 2\n"},
+		{name: "unsupported version", old: "Version: 1", new: "Version: 2"},
+		{name: "different modulus", old: "Modulus: 26", new: "Modulus: 27"},
+		{name: "different alphabet", old: "d6ec6898de87ddac", new: "0000000000000000"},
+		{name: "order mismatch", old: "Order: 2", new: "Order: 3"},
+		{name: "missing matrix", old: "\n3 3\n2 5"},
+		{name: "invalid entry", old: "3 3\n", new: "3 X\n"},
+		{name: "short row", old: "3 3\n", new: "3\n"},
+		{name: "invalid shift", old: "\n\n", new: "\nShift: 1 X\n\n"},
+		{name: "short shift", old: "\n\n", new: "\nShift: 1\n\n"},
+		{name: "not invertible", old: "3 3\n2 5", new: "2 0\n0 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := strings.Replace(valid, test.old, test.new, 1)
+			if _, err := cipher.ParseArmoredKey([]byte(data)); err == nil {
+				t.Errorf("ParseArmoredKey(\n%s) returned nil error, want non-nil", data)
+			}
+		})
+	}
+}
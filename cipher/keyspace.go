@@ -0,0 +1,43 @@
+package cipher
+
+import (
+	"math"
+	"math/big"
+)
+
+// KeySpaceSize returns the number of keys of the given order modulo m, i.e. the number of
+// invertible order×order matrices over Zm, |GL(n, Zm)|. By the CRT it is the product of
+// |GL(n, Z(p^k))| = p^((k-1)·n^2)·(p^n - 1)·(p^n - p)···(p^n - p^(n-1)) over the prime powers p^k
+// of m. Returns 0 for order < 1 or modulus < 2.
+func KeySpaceSize(order, modulus int) *big.Int {
+	size := new(big.Int)
+	if order < 1 || modulus < 2 {
+		return size
+	}
+	size.SetInt64(1)
+	for _, pp := range Factorize(modulus) {
+		p := big.NewInt(int64(pp.Prime))
+		pn := new(big.Int).Exp(p, big.NewInt(int64(order)), nil)
+		pi := big.NewInt(1)
+		for i := 0; i < order; i++ {
+			size.Mul(size, new(big.Int).Sub(pn, pi))
+			pi.Mul(pi, p)
+		}
+		size.Mul(size, new(big.Int).Exp(p, big.NewInt(int64((pp.Exp-1)*order*order)), nil))
+	}
+	return size
+}
+
+// KeySpaceBits returns the effective key space size in bits, log2(KeySpaceSize(order, modulus)),
+// which is the strength of the key against brute force. Returns 0 for order < 1 or modulus < 2.
+func KeySpaceBits(order, modulus int) float64 {
+	size := KeySpaceSize(order, modulus)
+	if size.Sign() == 0 {
+		return 0
+	}
+	// size = mant·2^exp with mant in [0.5, 1)
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(size).MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
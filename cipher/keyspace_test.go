@@ -0,0 +1,71 @@
+package cipher
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestKeySpaceSize verify known key space sizes
+func TestKeySpaceSize(t *testing.T) {
+	tests := []struct {
+		order, modulus int
+		want           string
+	}{
+		{order: 1, modulus: 26, want: "12"},
+		{order: 2, modulus: 26, want: "157248"},
+		{order: 2, modulus: 27, want: "314928"},
+		{order: 2, modulus: 29, want: "682080"},
+		{order: 3, modulus: 26, want: "1634038189056"},
+		{order: 3, modulus: 27, want: "4351506932448"},
+		{order: 3, modulus: 29, want: "13989670880640"},
+		{order: 2, modulus: 256, want: "1610612736"},
+		{order: 0, modulus: 26, want: "0"},
+		{order: 2, modulus: 1, want: "0"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("order %d mod %d", test.order, test.modulus), func(t *testing.T) {
+			if got := KeySpaceSize(test.order, test.modulus); got.String() != test.want {
+				t.Errorf("KeySpaceSize(%d, %d) = %s, want %s", test.order, test.modulus, got, test.want)
+			}
+		})
+	}
+}
+
+// TestKeySpaceSize_BruteForce verify key space sizes match the count of invertible matrices
+func TestKeySpaceSize_BruteForce(t *testing.T) {
+	for modulus := 2; modulus <= 12; modulus++ {
+		count := 0
+		for a := 0; a < modulus*modulus*modulus*modulus; a++ {
+			data := []int{a % modulus, a / modulus % modulus, a / modulus / modulus % modulus, a / modulus / modulus / modulus}
+			if m, _ := NewMatrix(2, data); m.IsInvertibleMod(modulus) {
+				count++
+			}
+		}
+		if got := KeySpaceSize(2, modulus); got.Cmp(big.NewInt(int64(count))) != 0 {
+			t.Errorf("KeySpaceSize(2, %d) = %s, want %d", modulus, got, count)
+		}
+	}
+}
+
+// TestKeySpaceBits verify key space sizes in bits
+func TestKeySpaceBits(t *testing.T) {
+	tests := []struct {
+		order, modulus int
+		want           float64
+	}{
+		{order: 2, modulus: 26, want: math.Log2(157248)},
+		{order: 3, modulus: 29, want: math.Log2(13989670880640)},
+		{order: 2, modulus: 2, want: math.Log2(6)},
+		{order: 10, modulus: 256, want: 798.2},
+		{order: 0, modulus: 26, want: 0},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("order %d mod %d", test.order, test.modulus), func(t *testing.T) {
+			if got := KeySpaceBits(test.order, test.modulus); math.Abs(got-test.want) > 0.05 {
+				t.Errorf("KeySpaceBits(%d, %d) = %f, want %f", test.order, test.modulus, got, test.want)
+			}
+		})
+	}
+}
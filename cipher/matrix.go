@@ -2,6 +2,7 @@ package cipher
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 )
 
@@ -31,6 +32,63 @@ func (m *Matrix) Order() int {
 	return m.order
 }
 
+// At returns the entry at row i and column j.
+func (m *Matrix) At(i, j int) (int, error) {
+	if 0 > i || i >= m.order || 0 > j || j >= m.order {
+		return 0, fmt.Errorf("got row and/or col out of bound")
+	}
+	return m.data[i][j], nil
+}
+
+// Row returns a copy of the i-th row.
+func (m *Matrix) Row(i int) ([]int, error) {
+	if 0 > i || i >= m.order {
+		return nil, fmt.Errorf("got row %d out of bound", i)
+	}
+	return append([]int(nil), m.data[i]...), nil
+}
+
+// Col returns a copy of the j-th column.
+func (m *Matrix) Col(j int) ([]int, error) {
+	if 0 > j || j >= m.order {
+		return nil, fmt.Errorf("got col %d out of bound", j)
+	}
+	col := make([]int, m.order)
+	for i, row := range m.data {
+		col[i] = row[j]
+	}
+	return col, nil
+}
+
+// Data returns a copy of the matrix entries, one slice per row, so changing it doesn't modify the
+// matrix.
+func (m *Matrix) Data() [][]int {
+	data := make([][]int, m.order)
+	for i, row := range m.data {
+		data[i] = append([]int(nil), row...)
+	}
+	return data
+}
+
+// Equal returns whether both matrices have the same order and entries. Nil matrices are only
+// equal to each other.
+func (m *Matrix) Equal(o *Matrix) bool {
+	if m == nil || o == nil {
+		return m == o
+	}
+	if m.order != o.order {
+		return false
+	}
+	for i, row := range m.data {
+		for j, x := range row {
+			if x != o.data[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // NewMatrix returns a new square matrix of the given order loaded with the given data.
 // The size of the input data must be exactly order squared (order^2).
 func NewMatrix(order int, data []int) (*Matrix, error) {
@@ -49,22 +107,84 @@ func NewMatrix(order int, data []int) (*Matrix, error) {
 	return m, nil
 }
 
-// Determinant returns the matrix determinant. This algorithm is extremely slow O(n!) since it
-// builds on the naive approach. Implement LU decomposition for better performance O(n^3).
+// Determinant returns the matrix determinant. It uses Bareiss' fraction-free Gaussian elimination,
+// which keeps every intermediate value an integer (a minor of the original matrix) and runs
+// in O(n^3). Returns an error if the determinant does not fit in an int, use BigDeterminant or
+// DeterminantMod for such matrices.
 func (m *Matrix) Determinant() (int, error) {
+	det, err := m.BigDeterminant()
+	if err != nil {
+		return 0, err
+	}
+	if !det.IsInt64() || int64(int(det.Int64())) != det.Int64() {
+		return 0, fmt.Errorf("determinant %s overflows int", det)
+	}
+	return int(det.Int64()), nil
+}
+
+// BigDeterminant returns the exact matrix determinant as an arbitrary-precision integer.
+// Intermediate products are kept in arbitrary precision as well since they may exceed the
+// final determinant by several orders of magnitude.
+func (m *Matrix) BigDeterminant() (*big.Int, error) {
+	if m.order < 1 {
+		return nil, fmt.Errorf("determinant is undefined for order < 1")
+	}
+	return m.bareiss(), nil
+}
+
+// bareiss returns the exact matrix determinant using Bareiss' algorithm. Assumes order >= 1.
+func (m *Matrix) bareiss() *big.Int {
+	a := make([][]*big.Int, m.order)
+	for i, row := range m.data {
+		a[i] = make([]*big.Int, m.order)
+		for j, x := range row {
+			a[i][j] = big.NewInt(int64(x))
+		}
+	}
+	sign, prev := 1, big.NewInt(1)
+	tmp := new(big.Int)
+	for k := 0; k < m.order-1; k++ {
+		if a[k][k].Sign() == 0 {
+			p := k + 1
+			for p < m.order && a[p][k].Sign() == 0 {
+				p++
+			}
+			if p == m.order {
+				return new(big.Int)
+			}
+			a[k], a[p] = a[p], a[k]
+			sign = -sign
+		}
+		for i := k + 1; i < m.order; i++ {
+			for j := k + 1; j < m.order; j++ {
+				a[i][j].Mul(a[i][j], a[k][k])
+				a[i][j].Sub(a[i][j], tmp.Mul(a[i][k], a[k][j]))
+				a[i][j].Quo(a[i][j], prev)
+			}
+		}
+		prev = a[k][k]
+	}
+	det := a[m.order-1][m.order-1]
+	if sign < 0 {
+		det.Neg(det)
+	}
+	return det
+}
+
+// DeterminantMod returns the residue of the matrix determinant modulo n. Rows are reduced to
+// upper triangular form using only unimodular operations (swaps and Euclidean row subtraction),
+// so it works for composite moduli where pivots may not be units.
+func (m *Matrix) DeterminantMod(n int) (int, error) {
 	if m.order < 1 {
 		return 0, fmt.Errorf("determinant is undefined for order < 1")
 	}
-	if m.order == 1 {
-		return m.data[0][0], nil
+	if n < 2 {
+		return 0, fmt.Errorf("got modulo < 2")
 	}
-	sign := 1
-	var det int
+	a := m.copyDataMod(n, 0)
+	det := Residue(triangulateMod(a, m.order, n), n)
 	for i := 0; i < m.order; i++ {
-		subM, _ := Minor(m, 0, i)
-		minor, _ := subM.Determinant()
-		det += sign * m.data[0][i] * minor
-		sign *= -1
+		det = ProductMod(det, a[i][i], n)
 	}
 	return det, nil
 }
@@ -83,39 +203,49 @@ func (m *Matrix) IsInvertibleMod(n int) bool {
 			}
 		}
 	}
-	det, err := m.Determinant()
+	det, err := m.DeterminantMod(n)
 	if err != nil {
 		return false
 	}
-	res := Residue(det, n)
-	if _, err := ModularInverse(res, n); err != nil {
+	if _, err := ModularInverse(det, n); err != nil {
 		return false
 	}
 
 	return true
 }
 
-// InverseMod returns a the inverted square matrix mod n.
+// InverseMod returns a the inverted square matrix mod n. The inverse is computed by Gauss-Jordan
+// elimination over Zn on the matrix augmented with the identity.
 func (m *Matrix) InverseMod(n int) (*Matrix, error) {
 	if n < 2 {
 		return nil, fmt.Errorf("got modulo < 2")
 	}
+	if m.order < 1 {
+		return nil, fmt.Errorf("cannot invert matrix of order %d < 1", m.order)
+	}
 	if !m.IsInvertibleMod(n) {
-		return nil, fmt.Errorf("matrix is not invertible mod %d", n)
+		return nil, fmt.Errorf("%w mod %d", ErrNotInvertible, n)
 	}
-	det, _ := m.Determinant() // Neglect error since its checked by IsInvertibleMod
-	res := Residue(det, n)
-	inverse, _ := ModularInverse(res, n) // Neglect error since its checked by IsInvertibleMod
-	adj, err := m.Adjoint()
-	if err != nil {
-		return nil, fmt.Errorf("failed to compute Adj(\n%s\n); %v", m, err)
+	a := m.copyDataMod(n, m.order)
+	for i := 0; i < m.order; i++ {
+		a[i][m.order+i] = 1
 	}
-	for i := range adj.data {
-		for j := range adj.data {
-			adj.data[i][j] = Residue(adj.data[i][j]*inverse, n)
+	triangulateMod(a, m.order, n)
+	for k := m.order - 1; k >= 0; k-- {
+		// Pivots are units since their product is det(A), which is a unit mod n
+		inverse, _ := ModularInverse(a[k][k], n)
+		for j := k; j < len(a[k]); j++ {
+			a[k][j] = ProductMod(a[k][j], inverse, n)
+		}
+		for i := 0; i < k; i++ {
+			subtractRowMod(a[i], a[k], a[i][k], n)
 		}
 	}
-	return adj, nil
+	inv := &Matrix{order: m.order, data: make([][]int, m.order)}
+	for i := range a {
+		inv.data[i] = a[i][m.order:]
+	}
+	return inv, nil
 }
 
 // Adjoint returns the adjoint matrix
@@ -149,6 +279,41 @@ func (m *Matrix) Cofactor() (*Matrix, error) {
 	return cof, nil
 }
 
+// AdjointMod returns the adjoint matrix with entries reduced mod n
+func (m *Matrix) AdjointMod(n int) (*Matrix, error) {
+	cof, err := m.CofactorMod(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute cofactor matrix mod %d for \n%s; %v", n, m, err)
+	}
+	return cof.Transpose(), nil
+}
+
+// CofactorMod returns the cofactor matrix with entries reduced mod n. Minors are computed in
+// arbitrary precision, so the result is exact even when Cofactor would overflow.
+func (m *Matrix) CofactorMod(n int) (*Matrix, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	bigN := big.NewInt(int64(n))
+	cof := &Matrix{order: m.order, data: make([][]int, m.order)}
+	for i := 0; i < m.order; i++ {
+		row := make([]int, m.order)
+		for j := 0; j < m.order; j++ {
+			minor, _ := Minor(m, i, j) // Error is neglected since row & col are always in bound
+			detM, err := minor.BigDeterminant()
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute det(m) for minor at row:%d col:%d\n%s;%v", i, j, m, err)
+			}
+			if (i+j)%2 != 0 {
+				detM.Neg(detM)
+			}
+			row[j] = int(detM.Mod(detM, bigN).Int64())
+		}
+		cof.data[i] = row
+	}
+	return cof, nil
+}
+
 // Transpose returns the transposed matrix
 func (m *Matrix) Transpose() *Matrix {
 	t := &Matrix{order: m.order, data: make([][]int, m.order)}
@@ -185,7 +350,9 @@ func Minor(m *Matrix, p, q int) (*Matrix, error) {
 	return r, nil
 }
 
-// VectorProductMod returns the matrix-vector product
+// VectorProductMod returns the matrix-vector product mod n. The result is correct regardless of
+// the size of the entries: when the sums of products could overflow an int, every partial product
+// and sum is reduced.
 func (m *Matrix) VectorProductMod(mod int, vector ...int) ([]int, error) {
 	if len(vector) != m.order {
 		return nil, fmt.Errorf("got invalid vector size %d, want %d", len(vector), m.order)
@@ -194,11 +361,197 @@ func (m *Matrix) VectorProductMod(mod int, vector ...int) ([]int, error) {
 		return nil, fmt.Errorf("got modulo < 2")
 	}
 	vp := make([]int, m.order)
+	if exactProducts(m.order, mod) && withinMod(mod, vector) && withinMod(mod, m.data...) {
+		for i, row := range m.data {
+			y := 0
+			for j, x := range row {
+				y += x * vector[j]
+			}
+			vp[i] = Residue(y, mod)
+		}
+		return vp, nil
+	}
 	for i := 0; i < m.order; i++ {
 		for j := 0; j < m.order; j++ {
-			vp[i] += m.data[i][j] * vector[j]
+			vp[i] = SumMod(vp[i], ProductMod(m.data[i][j], vector[j], mod), mod)
 		}
-		vp[i] = vp[i] % mod
 	}
 	return vp, nil
 }
+
+// Identity returns the identity matrix of the given order.
+func Identity(order int) (*Matrix, error) {
+	if order < 1 {
+		return nil, fmt.Errorf("cannot create identity matrix of order %d < 1", order)
+	}
+	return identity(order), nil
+}
+
+// MulMod returns the matrix product m·o mod n. Composing keys this way gives a single key, i.e.
+// encrypting with m·o is the same as encrypting with o and then with m.
+func (m *Matrix) MulMod(o *Matrix, n int) (*Matrix, error) {
+	if err := m.verifyOperand(o, n); err != nil {
+		return nil, err
+	}
+	return mulMod(m, o, n), nil
+}
+
+// AddMod returns the matrix sum m+o mod n.
+func (m *Matrix) AddMod(o *Matrix, n int) (*Matrix, error) {
+	if err := m.verifyOperand(o, n); err != nil {
+		return nil, err
+	}
+	sum := m.copyDataMod(n, 0)
+	for i, row := range sum {
+		for j := range row {
+			row[j] = SumMod(row[j], o.data[i][j], n)
+		}
+	}
+	return &Matrix{order: m.order, data: sum}, nil
+}
+
+// ScalarMulMod returns the product s·m mod n.
+func (m *Matrix) ScalarMulMod(s, n int) (*Matrix, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	product := m.copyDataMod(n, 0)
+	for _, row := range product {
+		for j := range row {
+			row[j] = ProductMod(s, row[j], n)
+		}
+	}
+	return &Matrix{order: m.order, data: product}, nil
+}
+
+// PowMod returns the matrix power m^k mod n using exponentiation by squaring, so it takes
+// O(log k) products. m^0 is the identity, and negative powers are powers of the inverse, which
+// fail if the matrix is not invertible mod n.
+func (m *Matrix) PowMod(k, n int) (*Matrix, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	if m.order < 1 {
+		return nil, fmt.Errorf("power is undefined for order < 1")
+	}
+	base := &Matrix{order: m.order, data: m.copyDataMod(n, 0)}
+	if k < 0 {
+		inverse, err := base.InverseMod(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute negative power; %w", err)
+		}
+		// -k overflows for the minimum int, so m^k is computed as (m^-1)^-(k+1) · m^-1
+		pow, _ := inverse.PowMod(-(k + 1), n) // Neglect error since k+1 <= 0 and the modulo is valid
+		return mulMod(pow, inverse, n), nil
+	}
+	pow := identity(m.order)
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			pow = mulMod(pow, base, n)
+		}
+		base = mulMod(base, base, n)
+	}
+	return pow, nil
+}
+
+// verifyOperand makes sure o can be operated with m mod n.
+func (m *Matrix) verifyOperand(o *Matrix, n int) error {
+	if n < 2 {
+		return fmt.Errorf("got modulo < 2")
+	}
+	if m.order != o.order {
+		return fmt.Errorf("got matrices of different order %d and %d", m.order, o.order)
+	}
+	return nil
+}
+
+// identity returns the identity matrix of the given order. Assumes order >= 1.
+func identity(order int) *Matrix {
+	id := &Matrix{order: order, data: make([][]int, order)}
+	for i := range id.data {
+		id.data[i] = make([]int, order)
+		id.data[i][i] = 1
+	}
+	return id
+}
+
+// mulMod returns the product a·b mod n. Both matrices must have the same order.
+func mulMod(a, b *Matrix, n int) *Matrix {
+	exact := exactProducts(a.order, n) && withinMod(n, a.data...) && withinMod(n, b.data...)
+	data := make([][]int, a.order)
+	for i := range data {
+		data[i] = make([]int, a.order)
+		for j := range data[i] {
+			if exact {
+				for k := 0; k < a.order; k++ {
+					data[i][j] += a.data[i][k] * b.data[k][j]
+				}
+				data[i][j] = Residue(data[i][j], n)
+				continue
+			}
+			for k := 0; k < a.order; k++ {
+				data[i][j] = SumMod(data[i][j], ProductMod(a.data[i][k], b.data[k][j], n), n)
+			}
+		}
+	}
+	return &Matrix{order: a.order, data: data}
+}
+
+// exactProducts returns whether a sum of order products of entries in (-n, n) fits in an int, so
+// it can be computed with plain arithmetic and reduced only once.
+func exactProducts(order, n int) bool {
+	bound := n - 1
+	return bound <= int(^uint32(0)>>1) && bound*bound <= int(^uint(0)>>1)/order
+}
+
+// withinMod returns whether every entry of rows is in (-n, n).
+func withinMod(n int, rows ...[]int) bool {
+	for _, row := range rows {
+		for _, x := range row {
+			if x <= -n || x >= n {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// copyDataMod returns a copy of the matrix entries reduced modulo n, with extra zeroed
+// columns appended to each row.
+func (m *Matrix) copyDataMod(n, extra int) [][]int {
+	data := make([][]int, m.order)
+	for i, row := range m.data {
+		data[i] = make([]int, m.order+extra)
+		for j, x := range row {
+			data[i][j] = Residue(x, n)
+		}
+	}
+	return data
+}
+
+// triangulateMod reduces the first order columns of the rows in a (entries in Zn) to upper
+// triangular form. Pivots are obtained through the Euclidean algorithm on rows, so no
+// modular inverse is needed. Returns the sign (1 or -1) introduced by row swaps.
+func triangulateMod(a [][]int, order, n int) int {
+	sign := 1
+	for k := 0; k < order; k++ {
+		for i := k + 1; i < order; i++ {
+			for a[i][k] != 0 {
+				subtractRowMod(a[k], a[i], a[k][k]/a[i][k], n)
+				a[k], a[i] = a[i], a[k]
+				sign = -sign
+			}
+		}
+	}
+	return sign
+}
+
+// subtractRowMod computes dst = dst - q*src (mod n) in place.
+func subtractRowMod(dst, src []int, q, n int) {
+	if q == 0 {
+		return
+	}
+	for j := range dst {
+		dst[j] = SumMod(dst[j], -ProductMod(q, src[j], n), n)
+	}
+}
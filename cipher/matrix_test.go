@@ -2,6 +2,8 @@ package cipher
 
 import (
 	"fmt"
+	"math/big"
+	"math/rand"
 	"testing"
 
 	cmp "github.com/google/go-cmp/cmp"
@@ -340,6 +342,162 @@ func TestDeterminant_InvalidOrder(t *testing.T) {
 	}
 }
 
+// diagonalMatrix returns a matrix of the given order with x along its diagonal
+func diagonalMatrix(order, x int) *Matrix {
+	m := &Matrix{order: order, data: make([][]int, order)}
+	for i := range m.data {
+		m.data[i] = make([]int, order)
+		m.data[i][i] = x
+	}
+	return m
+}
+
+// TestDeterminant_Overflow verifies determinants exceeding int are reported instead of wrapped
+func TestDeterminant_Overflow(t *testing.T) {
+	m := diagonalMatrix(8, 1000) // det = 10^24 > 2^63
+	if det, err := m.Determinant(); err == nil {
+		t.Errorf("Determinant() = %d, want overflow error", det)
+	}
+}
+
+// TestBigDeterminant verifies exact determinants beyond int range
+func TestBigDeterminant(t *testing.T) {
+	wantOrder8, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	tests := []struct {
+		name    string
+		matrix  *Matrix
+		wantDet *big.Int
+	}{
+		{
+			name:    "order 2",
+			matrix:  &Matrix{order: 2, data: [][]int{{1, 3}, {9, -1}}},
+			wantDet: big.NewInt(-28),
+		},
+		{
+			name:    "order 8 diagonal overflowing int",
+			matrix:  diagonalMatrix(8, 1000),
+			wantDet: wantOrder8,
+		},
+		{
+			name: "order 3 zero pivot",
+			matrix: &Matrix{
+				order: 3,
+				data: [][]int{
+					{0, 9, 3},
+					{2, 0, 4},
+					{3, 7, 0},
+				},
+			},
+			wantDet: big.NewInt(150),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotDet, err := test.matrix.BigDeterminant()
+			if err != nil {
+				t.Fatalf("BigDeterminant() returned an unexpected error; %v", err)
+			}
+			if gotDet.Cmp(test.wantDet) != 0 {
+				t.Errorf("BigDeterminant() = %s, want %s", gotDet, test.wantDet)
+			}
+		})
+	}
+	if _, err := (&Matrix{}).BigDeterminant(); err == nil {
+		t.Errorf("BigDeterminant() of order 0 did not fail, it should have")
+	}
+}
+
+// TestDeterminantMod verifies residue of determinant for prime and composite moduli
+func TestDeterminantMod(t *testing.T) {
+	order10 := &Matrix{
+		order: 10,
+		data: [][]int{
+			{52, 37, 38, 88, 89, 9, 23, 95, 99, 16},
+			{59, 23, 35, 36, 43, 13, 26, 46, 47, 85},
+			{7, 23, 84, 24, 83, 100, 30, 72, 86, 93},
+			{54, 94, 77, 59, 50, 29, 94, 64, 43, 37},
+			{68, 17, 65, 23, 19, 43, 68, 78, 15, 73},
+			{93, 96, 30, 86, 52, 55, 37, 58, 31, 22},
+			{58, 41, 85, 35, 18, 54, 26, 96, 43, 73},
+			{41, 88, 52, 36, 42, 6, 69, 12, 32, 3},
+			{72, 57, 9, 15, 78, 90, 63, 77, 17, 1},
+			{80, 49, 18, 67, 47, 22, 86, 13, 2, 33},
+		},
+	}
+	tests := []struct {
+		name    string
+		matrix  *Matrix
+		mod     int
+		wantDet int
+	}{
+		{
+			name:    "order 1",
+			matrix:  &Matrix{order: 1, data: [][]int{{30}}},
+			mod:     26,
+			wantDet: 4,
+		},
+		{
+			name:    "order 2 negative entries mod 26",
+			matrix:  &Matrix{order: 2, data: [][]int{{1, 3}, {9, -1}}},
+			mod:     26,
+			wantDet: 24,
+		},
+		{
+			name:    "order 2 negative entries mod 27",
+			matrix:  &Matrix{order: 2, data: [][]int{{1, 3}, {9, -1}}},
+			mod:     27,
+			wantDet: 26,
+		},
+		{
+			name: "order 3 non-unit pivots mod 26",
+			matrix: &Matrix{
+				order: 3,
+				data: [][]int{
+					{2, 13, 4},
+					{13, 2, 0},
+					{4, 0, 13},
+				},
+			},
+			mod:     26,
+			wantDet: 7,
+		},
+		{name: "order 10 mod 2", matrix: order10, mod: 2, wantDet: 0},
+		{name: "order 10 mod 26", matrix: order10, mod: 26, wantDet: 2},
+		{name: "order 10 mod 27", matrix: order10, mod: 27, wantDet: 11},
+		{name: "order 8 overflowing int mod 27", matrix: diagonalMatrix(8, 1000), mod: 27, wantDet: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotDet, err := test.matrix.DeterminantMod(test.mod)
+			if err != nil {
+				t.Fatalf("DeterminantMod(%d) returned an unexpected error; %v", test.mod, err)
+			}
+			if gotDet != test.wantDet {
+				t.Errorf("DeterminantMod(%d) = %d, want %d for matrix\n%s", test.mod, gotDet, test.wantDet, test.matrix)
+			}
+		})
+	}
+}
+
+// TestDeterminantMod_Error verifies validations
+func TestDeterminantMod_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *Matrix
+		mod    int
+	}{
+		{name: "order 0", matrix: &Matrix{}, mod: 26},
+		{name: "mod less than 2", matrix: &Matrix{order: 1, data: [][]int{{1}}}, mod: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.matrix.DeterminantMod(test.mod); err == nil {
+				t.Errorf("DeterminantMod(%d) did not fail, it should have", test.mod)
+			}
+		})
+	}
+}
+
 // TestIsInvertibleMod
 func TestIsInvertibleMod(t *testing.T) {
 	tests := []struct {
@@ -576,6 +734,86 @@ func TestCofactor_Error(t *testing.T) {
 	}
 }
 
+// TestCofactorMod verifies cofactor residues, including minors that overflow int
+func TestCofactorMod(t *testing.T) {
+	tests := []struct {
+		name            string
+		mod             int
+		matrix, wantCof *Matrix
+	}{
+		{
+			name: "order 3",
+			mod:  26,
+			matrix: &Matrix{
+				order: 3,
+				data: [][]int{
+					{0, 9, 3},
+					{2, 0, 4},
+					{3, 7, 0},
+				},
+			},
+			wantCof: &Matrix{
+				order: 3,
+				data: [][]int{
+					{24, 12, 14},
+					{21, 17, 1},
+					{10, 6, 8},
+				},
+			},
+		},
+		{
+			name:    "order 8 with minors overflowing int",
+			mod:     1000003,
+			matrix:  diagonalMatrix(8, 1000),
+			wantCof: diagonalMatrix(8, 973003),
+		},
+	}
+	unxOpt := cmp.AllowUnexported(Matrix{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.matrix.Cofactor(); test.matrix.order == 8 && err == nil {
+				t.Errorf("Cofactor() did not fail on overflowing minors, it should have")
+			}
+			cof, err := test.matrix.CofactorMod(test.mod)
+			if err != nil {
+				t.Fatalf("CofactorMod(%d) returned unexpected error; %v\n%s", test.mod, err, test.matrix)
+			}
+			if diff := cmp.Diff(test.wantCof, cof, unxOpt); diff != "" {
+				t.Errorf("CofactorMod(%d) =\n%s, want\n%s; diff want -> got:\n%s", test.mod, cof, test.wantCof, diff)
+			}
+			adj, err := test.matrix.AdjointMod(test.mod)
+			if err != nil {
+				t.Fatalf("AdjointMod(%d) returned unexpected error; %v\n%s", test.mod, err, test.matrix)
+			}
+			if diff := cmp.Diff(test.wantCof.Transpose(), adj, unxOpt); diff != "" {
+				t.Errorf("AdjointMod(%d) =\n%s; diff want -> got:\n%s", test.mod, adj, diff)
+			}
+		})
+	}
+}
+
+// TestCofactorMod_Error verifies validations
+func TestCofactorMod_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *Matrix
+		mod    int
+	}{
+		{name: "order 1", matrix: &Matrix{order: 1, data: [][]int{{1}}}, mod: 26},
+		{name: "mod less than 2", matrix: diagonalMatrix(2, 1), mod: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.matrix.CofactorMod(test.mod); err == nil {
+				t.Errorf("CofactorMod(%d) did not fail, it should have", test.mod)
+			}
+			if _, err := test.matrix.AdjointMod(test.mod); err == nil {
+				t.Errorf("AdjointMod(%d) did not fail, it should have", test.mod)
+			}
+		})
+	}
+}
+
 // TestTranspose verify method implementation
 func TestTranspose(t *testing.T) {
 	tests := []struct {
@@ -781,6 +1019,12 @@ func TestInverseMod(t *testing.T) {
 		mod                int
 		matrix, wantMatrix *Matrix
 	}{
+		{
+			name:       "order 1 mod 26",
+			mod:        26,
+			matrix:     &Matrix{order: 1, data: [][]int{{7}}},
+			wantMatrix: &Matrix{order: 1, data: [][]int{{15}}},
+		},
 		{
 			name: "order 2 mod 12",
 			mod:  12,
@@ -854,6 +1098,49 @@ func TestInverseMod(t *testing.T) {
 	}
 }
 
+// TestInverseMod_LargeOrder verifies inverses of large random matrices satisfy A·A^-1 = I
+func TestInverseMod_LargeOrder(t *testing.T) {
+	tests := []struct {
+		order, mod int
+	}{
+		{order: 12, mod: 26},
+		{order: 50, mod: 27},
+		{order: 100, mod: 26},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("order %d mod %d", test.order, test.mod), func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(int64(test.order)))
+			var m *Matrix
+			for m == nil || !m.IsInvertibleMod(test.mod) {
+				data := make([]int, test.order*test.order)
+				for i := range data {
+					data[i] = rnd.Intn(test.mod)
+				}
+				m, _ = NewMatrix(test.order, data)
+			}
+			inverse, err := m.InverseMod(test.mod)
+			if err != nil {
+				t.Fatalf("InverseMod(%d) returned unexpected error; %v", test.mod, err)
+			}
+			for i := 0; i < test.order; i++ {
+				for j := 0; j < test.order; j++ {
+					var x int
+					for k := 0; k < test.order; k++ {
+						x += m.data[i][k] * inverse.data[k][j]
+					}
+					want := 0
+					if i == j {
+						want = 1
+					}
+					if Residue(x, test.mod) != want {
+						t.Fatalf("(A·A^-1)[%d][%d] = %d, want %d", i, j, Residue(x, test.mod), want)
+					}
+				}
+			}
+		})
+	}
+}
+
 // TestInverseMod_Error verifies error check in InverseMod
 func TestInverseMod_Error(t *testing.T) {
 	tests := []struct {
@@ -867,8 +1154,13 @@ func TestInverseMod_Error(t *testing.T) {
 			mod:    1,
 		},
 		{
-			name:   "order 1",
-			matrix: &Matrix{order: 1, data: [][]int{{1}}},
+			name:   "order 0",
+			matrix: &Matrix{},
+			mod:    12,
+		},
+		{
+			name:   "order 1 without inverse",
+			matrix: &Matrix{order: 1, data: [][]int{{4}}},
 			mod:    12,
 		},
 		{
@@ -939,6 +1231,45 @@ func TestVectorProductMod(t *testing.T) {
 			multVector: []int{2, 15, 13},
 			wantVector: []int{10, 21, 20},
 		},
+		{
+			name: "entries whose products overflow int",
+			mod:  1000003,
+			matrix: &Matrix{
+				order: 2,
+				data: [][]int{
+					{1 << 62, 3},
+					{5, 1 << 61},
+				},
+			},
+			multVector: []int{1 << 62, 7},
+			wantVector: []int{625212, 120208},
+		},
+		{
+			name: "negative entries",
+			mod:  27,
+			matrix: &Matrix{
+				order: 2,
+				data: [][]int{
+					{-5, 15},
+					{20, -26},
+				},
+			},
+			multVector: []int{-2, 13},
+			wantVector: []int{16, 0},
+		},
+		{
+			name: "residues whose products overflow int",
+			mod:  1<<61 - 1,
+			matrix: &Matrix{
+				order: 2,
+				data: [][]int{
+					{1<<61 - 2, 1<<61 - 3},
+					{3, 1<<61 - 6},
+				},
+			},
+			multVector: []int{1<<61 - 4, 1<<61 - 8},
+			wantVector: []int{17, 26},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -1016,3 +1347,267 @@ func TestMatrixOrder(t *testing.T) {
 		})
 	}
 }
+
+// TestMatrixAccessors verify entries, rows and columns are read correctly
+func TestMatrixAccessors(t *testing.T) {
+	m := &Matrix{order: 3, data: [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}}
+	for i := 0; i < m.order; i++ {
+		for j := 0; j < m.order; j++ {
+			if got, err := m.At(i, j); err != nil || got != 3*i+j+1 {
+				t.Errorf("At(%d, %d) = %d, %v, want %d, nil", i, j, got, err, 3*i+j+1)
+			}
+		}
+	}
+	if got, err := m.Row(1); err != nil || !cmp.Equal(got, []int{4, 5, 6}) {
+		t.Errorf("Row(1) = %v, %v, want [4 5 6], nil", got, err)
+	}
+	if got, err := m.Col(1); err != nil || !cmp.Equal(got, []int{2, 5, 8}) {
+		t.Errorf("Col(1) = %v, %v, want [2 5 8], nil", got, err)
+	}
+	if diff := cmp.Diff(m.data, m.Data()); diff != "" {
+		t.Errorf("Data() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Returned slices are copies
+	row, _ := m.Row(0)
+	col, _ := m.Col(0)
+	data := m.Data()
+	row[0], col[0], data[0][0] = -1, -1, -1
+	if m.data[0][0] != 1 {
+		t.Errorf("modifying the result of Row, Col or Data changed the matrix to\n%s", m)
+	}
+}
+
+// TestMatrixAccessors_Error verify indexes out of bound are rejected
+func TestMatrixAccessors_Error(t *testing.T) {
+	m := &Matrix{order: 2, data: [][]int{{1, 2}, {3, 4}}}
+	for _, i := range []int{-1, 2} {
+		if _, err := m.At(i, 0); err == nil {
+			t.Errorf("At(%d, 0) returned nil error, want non-nil", i)
+		}
+		if _, err := m.At(0, i); err == nil {
+			t.Errorf("At(0, %d) returned nil error, want non-nil", i)
+		}
+		if _, err := m.Row(i); err == nil {
+			t.Errorf("Row(%d) returned nil error, want non-nil", i)
+		}
+		if _, err := m.Col(i); err == nil {
+			t.Errorf("Col(%d) returned nil error, want non-nil", i)
+		}
+	}
+}
+
+// TestMatrixEqual verify matrices are compared by order and entries
+func TestMatrixEqual(t *testing.T) {
+	m := &Matrix{order: 2, data: [][]int{{1, 2}, {3, 4}}}
+	tests := []struct {
+		name  string
+		other *Matrix
+		want  bool
+	}{
+		{name: "same entries", other: &Matrix{order: 2, data: [][]int{{1, 2}, {3, 4}}}, want: true},
+		{name: "different entry", other: &Matrix{order: 2, data: [][]int{{1, 2}, {3, 5}}}, want: false},
+		{name: "different order", other: &Matrix{order: 1, data: [][]int{{1}}}, want: false},
+		{name: "nil", other: nil, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := m.Equal(test.other); got != test.want {
+				t.Errorf("Equal(\n%v) = %t, want %t", test.other, got, test.want)
+			}
+		})
+	}
+	var null *Matrix
+	if !null.Equal(nil) {
+		t.Errorf("nil.Equal(nil) = false, want true")
+	}
+}
+
+// TestIdentity verify identity matrices are created correctly
+func TestIdentity(t *testing.T) {
+	tests := []struct {
+		order      int
+		wantMatrix *Matrix
+	}{
+		{order: 1, wantMatrix: &Matrix{order: 1, data: [][]int{{1}}}},
+		{order: 3, wantMatrix: &Matrix{order: 3, data: [][]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("order %d", test.order), func(t *testing.T) {
+			got, err := Identity(test.order)
+			if err != nil {
+				t.Fatalf("Identity(%d) returned unexpected error; %v", test.order, err)
+			}
+			if diff := cmp.Diff(test.wantMatrix, got, cmp.AllowUnexported(Matrix{})); diff != "" {
+				t.Errorf("Identity(%d) mismatch (-want +got):\n%s", test.order, diff)
+			}
+		})
+	}
+	if _, err := Identity(0); err == nil {
+		t.Errorf("Identity(0) returned nil error, want non-nil")
+	}
+}
+
+// TestMatrixArithmeticMod verify products, sums and scalar products mod n
+func TestMatrixArithmeticMod(t *testing.T) {
+	a := &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}
+	b := &Matrix{order: 2, data: [][]int{{15, 17}, {20, 9}}}
+	tests := []struct {
+		name       string
+		op         func() (*Matrix, error)
+		wantMatrix *Matrix
+	}{
+		{
+			name:       "product of inverses",
+			op:         func() (*Matrix, error) { return a.MulMod(b, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{1, 0}, {0, 1}}},
+		},
+		{
+			name:       "product",
+			op:         func() (*Matrix, error) { return a.MulMod(a, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{15, 24}, {16, 5}}},
+		},
+		{
+			name:       "product with negative entries",
+			op:         func() (*Matrix, error) { return a.MulMod(&Matrix{order: 2, data: [][]int{{-1, 0}, {0, -1}}}, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{23, 23}, {24, 21}}},
+		},
+		{
+			name:       "sum",
+			op:         func() (*Matrix, error) { return a.AddMod(b, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{18, 20}, {22, 14}}},
+		},
+		{
+			name:       "sum with negative entries",
+			op:         func() (*Matrix, error) { return a.AddMod(&Matrix{order: 2, data: [][]int{{-3, -4}, {-30, 0}}}, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{0, 25}, {24, 5}}},
+		},
+		{
+			name:       "scalar product",
+			op:         func() (*Matrix, error) { return a.ScalarMulMod(9, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{1, 1}, {18, 19}}},
+		},
+		{
+			name:       "negative scalar product",
+			op:         func() (*Matrix, error) { return a.ScalarMulMod(-1, 26) },
+			wantMatrix: &Matrix{order: 2, data: [][]int{{23, 23}, {24, 21}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.op()
+			if err != nil {
+				t.Fatalf("returned unexpected error; %v", err)
+			}
+			if diff := cmp.Diff(test.wantMatrix, got, cmp.AllowUnexported(Matrix{})); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestMatrixArithmeticMod_Error verify invalid operands and modulo are rejected
+func TestMatrixArithmeticMod_Error(t *testing.T) {
+	a := &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}
+	b := &Matrix{order: 3, data: [][]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}
+	tests := []struct {
+		name string
+		op   func() (*Matrix, error)
+	}{
+		{name: "product of different order", op: func() (*Matrix, error) { return a.MulMod(b, 26) }},
+		{name: "product mod 1", op: func() (*Matrix, error) { return a.MulMod(a, 1) }},
+		{name: "sum of different order", op: func() (*Matrix, error) { return a.AddMod(b, 26) }},
+		{name: "sum mod 1", op: func() (*Matrix, error) { return a.AddMod(a, 1) }},
+		{name: "scalar product mod 1", op: func() (*Matrix, error) { return a.ScalarMulMod(2, 1) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.op(); err == nil {
+				t.Errorf("returned nil error, want non-nil")
+			}
+		})
+	}
+}
+
+// TestPowMod verify matrix powers match repeated products
+func TestPowMod(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for _, n := range []int{2, 26, 27, 256} {
+		for order := 1; order <= 4; order++ {
+			data := make([]int, order*order)
+			for i := range data {
+				data[i] = rnd.Intn(2*n) - n
+			}
+			m, _ := NewMatrix(order, data)
+			want, _ := Identity(order)
+			for k := 0; k <= 10; k++ {
+				got, err := m.PowMod(k, n)
+				if err != nil {
+					t.Fatalf("PowMod(%d, %d) returned unexpected error; %v", k, n, err)
+				}
+				if !got.Equal(want) {
+					t.Errorf("PowMod(%d, %d) of\n%s=\n%s, want\n%s", k, n, m, got, want)
+				}
+				want, _ = want.MulMod(m, n)
+			}
+		}
+	}
+}
+
+// TestPowMod_Negative verify negative powers are powers of the inverse
+func TestPowMod_Negative(t *testing.T) {
+	m := &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}
+	inverse, _ := m.InverseMod(26)
+	for _, k := range []int{-1, -2, -7} {
+		got, err := m.PowMod(k, 26)
+		if err != nil {
+			t.Fatalf("PowMod(%d, 26) returned unexpected error; %v", k, err)
+		}
+		want, _ := inverse.PowMod(-k, 26)
+		if !got.Equal(want) {
+			t.Errorf("PowMod(%d, 26) =\n%s, want\n%s", k, got, want)
+		}
+		pow, _ := m.PowMod(-k, 26)
+		if id, _ := got.MulMod(pow, 26); !id.Equal(identity(2)) {
+			t.Errorf("PowMod(%d, 26)·PowMod(%d, 26) =\n%s, want identity", k, -k, id)
+		}
+	}
+
+	// Order 1 matrices are inverted as residues
+	scalar := &Matrix{order: 1, data: [][]int{{7}}}
+	if got, err := scalar.PowMod(-1, 26); err != nil || !got.Equal(&Matrix{order: 1, data: [][]int{{15}}}) {
+		t.Errorf("PowMod(-1, 26) of\n%s= %v, %v; want [15], nil", scalar, got, err)
+	}
+
+	// m^min · m^max · m = m^0
+	maxInt := int(^uint(0) >> 1)
+	minPow, err := m.PowMod(-maxInt-1, 26)
+	if err != nil {
+		t.Fatalf("PowMod(%d, 26) returned unexpected error; %v", -maxInt-1, err)
+	}
+	maxPow, _ := m.PowMod(maxInt, 26)
+	product, _ := minPow.MulMod(maxPow, 26)
+	if product, _ = product.MulMod(m, 26); !product.Equal(identity(2)) {
+		t.Errorf("PowMod(%d, 26)·PowMod(%d, 26)·m =\n%s, want identity", -maxInt-1, maxInt, product)
+	}
+}
+
+// TestPowMod_Error verify invalid powers are rejected
+func TestPowMod_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *Matrix
+		k, n   int
+	}{
+		{name: "mod 1", matrix: &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}, k: 2, n: 1},
+		{name: "order 0", matrix: &Matrix{}, k: 2, n: 26},
+		{name: "negative power of singular matrix", matrix: &Matrix{order: 2, data: [][]int{{2, 0}, {0, 1}}}, k: -1, n: 26},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.matrix.PowMod(test.k, test.n); err == nil {
+				t.Errorf("PowMod(%d, %d) returned nil error, want non-nil", test.k, test.n)
+			}
+		})
+	}
+}
@@ -2,18 +2,31 @@ package cipher
 
 import (
 	"fmt"
-	"math"
+	"math/bits"
 )
 
 // Residue returns the residue of a modulo m
 func Residue(a, m int) int {
-	reminder := int(math.Abs(float64(a % m)))
-	if a >= 0 {
-		return reminder
-	} else if a < 0 && reminder != 0 {
-		return m - reminder
+	reminder := a % m
+	if reminder < 0 {
+		return reminder + m
 	}
-	return 0
+	return reminder
+}
+
+// SumMod returns (a + b) mod m without overflowing, even when m is close to the int limit.
+func SumMod(a, b, m int) int {
+	a, b = Residue(a, m), Residue(b, m)
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
+
+// ProductMod returns (a * b) mod m. The product is computed in 128 bits so it never overflows.
+func ProductMod(a, b, m int) int {
+	hi, lo := bits.Mul64(uint64(Residue(a, m)), uint64(Residue(b, m)))
+	return int(bits.Rem64(hi, lo, uint64(m)))
 }
 
 // EGCD computes the Bezouts identity using the Extended Euclidean Algorithm.
@@ -55,3 +68,62 @@ func ModularInverse(a, m int) (int, error) {
 	}
 	return x, nil
 }
+
+// PrimePower represents the factor Prime^Exp of an integer factorization
+type PrimePower struct {
+	Prime, Exp int
+}
+
+// Value returns Prime^Exp
+func (pp PrimePower) Value() int {
+	v := 1
+	for i := 0; i < pp.Exp; i++ {
+		v *= pp.Prime
+	}
+	return v
+}
+
+// Factorize returns the prime factorization of n in ascending order of primes. Returns an empty
+// factorization for n < 2.
+func Factorize(n int) []PrimePower {
+	var factors []PrimePower
+	for p := 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		pp := PrimePower{Prime: p}
+		for n%p == 0 {
+			n /= p
+			pp.Exp++
+		}
+		factors = append(factors, pp)
+	}
+	if n > 1 {
+		factors = append(factors, PrimePower{Prime: n, Exp: 1})
+	}
+	return factors
+}
+
+// CRT returns the unique x in [0, M) such that x = residues[i] (mod moduli[i]) for every i, where
+// M is the product of the moduli, using the Chinese Remainder Theorem. Moduli must be pairwise
+// coprime.
+func CRT(residues, moduli []int) (int, error) {
+	if len(residues) != len(moduli) {
+		return 0, fmt.Errorf("got %d residues for %d moduli", len(residues), len(moduli))
+	}
+	x, m := 0, 1
+	for i, mi := range moduli {
+		if mi < 1 {
+			return 0, fmt.Errorf("got modulo %d < 1", mi)
+		}
+		// Find t such that x + m*t = residues[i] (mod mi)
+		inverse, err := ModularInverse(Residue(m, mi), mi)
+		if err != nil {
+			return 0, fmt.Errorf("moduli are not pairwise coprime; %v", err)
+		}
+		t := ProductMod(residues[i]-x, inverse, mi)
+		x += m * t
+		m *= mi
+	}
+	return x, nil
+}
@@ -3,6 +3,8 @@ package cipher
 import (
 	"fmt"
 	"testing"
+
+	cmp "github.com/google/go-cmp/cmp"
 )
 
 // TestResidue check definition of Residue function
@@ -14,6 +16,7 @@ func TestResidue(t *testing.T) {
 		{a: 8590, mod: 27, wantResidue: 4},
 		{a: -38, mod: 26, wantResidue: 14},
 		{a: -26, mod: 26, wantResidue: 0},
+		{a: -1152921504606846977, mod: 1152921504606846979, wantResidue: 2},
 	}
 	for _, test := range tests {
 		name := fmt.Sprintf("Reminder(a:%d, m:%d)", test.a, test.mod)
@@ -26,6 +29,44 @@ func TestResidue(t *testing.T) {
 	}
 }
 
+// TestSumMod verifies modular addition, including sums that overflow int
+func TestSumMod(t *testing.T) {
+	tests := []struct {
+		a, b, mod, want int
+	}{
+		{a: 20, b: 10, mod: 26, want: 4},
+		{a: -3, b: 1, mod: 26, want: 24},
+		{a: 4611686018427387902, b: 4611686018427387901, mod: 4611686018427387903, want: 4611686018427387900},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("SumMod(a:%d, b:%d, m:%d)", test.a, test.b, test.mod)
+		t.Run(name, func(t *testing.T) {
+			if got := SumMod(test.a, test.b, test.mod); got != test.want {
+				t.Errorf("%s = %d, want %d", name, got, test.want)
+			}
+		})
+	}
+}
+
+// TestProductMod verifies modular multiplication, including products that overflow int
+func TestProductMod(t *testing.T) {
+	tests := []struct {
+		a, b, mod, want int
+	}{
+		{a: 7, b: 8, mod: 26, want: 4},
+		{a: -3, b: 5, mod: 26, want: 11},
+		{a: 4611686018427387902, b: 4611686018427387901, mod: 4611686018427387903, want: 2},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("ProductMod(a:%d, b:%d, m:%d)", test.a, test.b, test.mod)
+		t.Run(name, func(t *testing.T) {
+			if got := ProductMod(test.a, test.b, test.mod); got != test.want {
+				t.Errorf("%s = %d, want %d", name, got, test.want)
+			}
+		})
+	}
+}
+
 // TestEGCD verify correc implementation of Extended Euclidean Algorithm
 func TestEGCD(t *testing.T) {
 	tests := []struct {
@@ -201,3 +242,78 @@ func TestModularInverse_Error(t *testing.T) {
 		}
 	}
 }
+
+// TestFactorize verify prime factorizations
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []PrimePower
+	}{
+		{n: 1},
+		{n: 2, want: []PrimePower{{Prime: 2, Exp: 1}}},
+		{n: 26, want: []PrimePower{{Prime: 2, Exp: 1}, {Prime: 13, Exp: 1}}},
+		{n: 27, want: []PrimePower{{Prime: 3, Exp: 3}}},
+		{n: 29, want: []PrimePower{{Prime: 29, Exp: 1}}},
+		{n: 360, want: []PrimePower{{Prime: 2, Exp: 3}, {Prime: 3, Exp: 2}, {Prime: 5, Exp: 1}}},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("Factorize(%d)", test.n)
+		t.Run(name, func(t *testing.T) {
+			got := Factorize(test.n)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("%s = %v, want %v; diff want -> got %s", name, got, test.want, diff)
+			}
+			v := 1
+			for _, pp := range got {
+				v *= pp.Value()
+			}
+			if test.n > 1 && v != test.n {
+				t.Errorf("product of %s factors = %d, want %d", name, v, test.n)
+			}
+		})
+	}
+}
+
+// TestCRT verify Chinese Remainder Theorem solutions
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		residues, moduli []int
+		want             int
+	}{
+		{residues: []int{2, 3, 2}, moduli: []int{3, 5, 7}, want: 23},
+		{residues: []int{1, 12}, moduli: []int{2, 13}, want: 25},
+		{residues: []int{-1, 0}, moduli: []int{2, 13}, want: 13},
+		{residues: []int{}, moduli: []int{}, want: 0},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("CRT(%v, %v)", test.residues, test.moduli)
+		t.Run(name, func(t *testing.T) {
+			got, err := CRT(test.residues, test.moduli)
+			if err != nil {
+				t.Fatalf("%s returned unexpected error; %v", name, err)
+			}
+			if got != test.want {
+				t.Errorf("%s = %d, want %d", name, got, test.want)
+			}
+		})
+	}
+}
+
+// TestCRT_Error verify validations
+func TestCRT_Error(t *testing.T) {
+	tests := []struct {
+		name             string
+		residues, moduli []int
+	}{
+		{name: "different lengths", residues: []int{1}, moduli: []int{2, 3}},
+		{name: "modulo less than 1", residues: []int{1}, moduli: []int{0}},
+		{name: "moduli not coprime", residues: []int{1, 2}, moduli: []int{4, 6}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := CRT(test.residues, test.moduli); err == nil {
+				t.Errorf("CRT(%v, %v) returned nil error, want non-nil", test.residues, test.moduli)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package cipher
+
+import "fmt"
+
+// BlockFunc transforms the consecutive blocks of a single message, given as residues modulo the
+// alphabet size. It may keep state between calls (e.g. the previous cipher block), so a new one
+// must be used for every message.
+type BlockFunc func(block []int) []int
+
+// Mode is a mode of operation, which defines how the encryption of consecutive blocks of a
+// message is chained. Ciphers use ECB when no mode is set.
+type Mode interface {
+	// Encrypter returns the function that encrypts the blocks of a message using key mod n.
+	Encrypter(key *Matrix, n int) (BlockFunc, error)
+	// Decrypter returns the function that decrypts the blocks of a message encrypted using key
+	// mod n.
+	Decrypter(key *Matrix, n int) (BlockFunc, error)
+}
+
+// inverseDecrypter is implemented by modes whose decryption only needs the inverted key, so an
+// inverse computed beforehand (e.g. by a Session) is reused instead of inverting the key again.
+type inverseDecrypter interface {
+	// inverseDecrypter returns the function that decrypts the blocks of a message using the
+	// inverted key mod n.
+	inverseDecrypter(inverse *Matrix, n int) (BlockFunc, error)
+}
+
+// WithMode makes the cipher chain blocks using the given mode of operation.
+func WithMode(m Mode) Option {
+	return func(c *Cipher) {
+		c.mode = m
+	}
+}
+
+// ECB (electronic codebook) encrypts every block independently with the same key, the classic
+// Hill Cipher. Repeated plain text blocks produce repeated cipher text blocks.
+type ECB struct{}
+
+// Encrypter implements Mode
+func (ECB) Encrypter(key *Matrix, n int) (BlockFunc, error) {
+	return func(block []int) []int {
+		c, _ := key.VectorProductMod(n, block...) // Neglect error since block has key's order
+		return c
+	}, nil
+}
+
+// Decrypter implements Mode
+func (ECB) Decrypter(key *Matrix, n int) (BlockFunc, error) {
+	inverse, err := key.InverseMod(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invert key; %w", err)
+	}
+	return ECB{}.inverseDecrypter(inverse, n)
+}
+
+// inverseDecrypter implements inverseDecrypter
+func (ECB) inverseDecrypter(inverse *Matrix, n int) (BlockFunc, error) {
+	return ECB{}.Encrypter(inverse, n)
+}
+
+// CBC (cipher block chaining) adds the previous cipher block (the IV for the first one) to each
+// plain text block before encrypting it, that is, C_i = K·(P_i + C_i-1) mod n. IV must have as
+// many entries as the key's order.
+type CBC struct {
+	IV []int
+}
+
+// iv returns a copy of the IV reduced mod n. Returns an error if its size doesn't match order.
+func (m CBC) iv(order, n int) ([]int, error) {
+	if len(m.IV) != order {
+		return nil, fmt.Errorf("IV must have %d entries, got %d", order, len(m.IV))
+	}
+	iv := make([]int, order)
+	for i, x := range m.IV {
+		iv[i] = Residue(x, n)
+	}
+	return iv, nil
+}
+
+// Encrypter implements Mode
+func (m CBC) Encrypter(key *Matrix, n int) (BlockFunc, error) {
+	prev, err := m.iv(key.order, n)
+	if err != nil {
+		return nil, err
+	}
+	return func(block []int) []int {
+		for i, x := range block {
+			prev[i] = SumMod(x, prev[i], n)
+		}
+		c, _ := key.VectorProductMod(n, prev...) // Neglect error since block has key's order
+		copy(prev, c)
+		return c
+	}, nil
+}
+
+// Decrypter implements Mode
+func (m CBC) Decrypter(key *Matrix, n int) (BlockFunc, error) {
+	if _, err := m.iv(key.order, n); err != nil {
+		return nil, err
+	}
+	inverse, err := key.InverseMod(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invert key; %w", err)
+	}
+	return m.inverseDecrypter(inverse, n)
+}
+
+// inverseDecrypter implements inverseDecrypter
+func (m CBC) inverseDecrypter(inverse *Matrix, n int) (BlockFunc, error) {
+	prev, err := m.iv(inverse.order, n)
+	if err != nil {
+		return nil, err
+	}
+	return func(block []int) []int {
+		p, _ := inverse.VectorProductMod(n, block...) // Neglect error since block has key's order
+		for i, x := range block {
+			p[i] = Residue(p[i]-prev[i], n)
+			prev[i] = Residue(x, n)
+		}
+		return p
+	}, nil
+}
+
+// CTR (counter) adds to each plain text block a keystream vector K·T_i mod n, where the counter
+// T_i starts at Nonce and is incremented by one after every block (as a base n number whose most
+// significant digit comes first). Nonce must have as many entries as the key's order.
+type CTR struct {
+	Nonce []int
+}
+
+// keystream returns a function that yields the next keystream vector on every call.
+func (m CTR) keystream(key *Matrix, n int) (func() []int, error) {
+	if len(m.Nonce) != key.order {
+		return nil, fmt.Errorf("nonce must have %d entries, got %d", key.order, len(m.Nonce))
+	}
+	counter := make([]int, key.order)
+	for i, x := range m.Nonce {
+		counter[i] = Residue(x, n)
+	}
+	return func() []int {
+		ks, _ := key.VectorProductMod(n, counter...) // Neglect error since counter has key's order
+		for i := len(counter) - 1; i >= 0; i-- {
+			counter[i] = SumMod(counter[i], 1, n)
+			if counter[i] != 0 {
+				break
+			}
+		}
+		return ks
+	}, nil
+}
+
+// Encrypter implements Mode
+func (m CTR) Encrypter(key *Matrix, n int) (BlockFunc, error) {
+	next, err := m.keystream(key, n)
+	if err != nil {
+		return nil, err
+	}
+	return func(block []int) []int {
+		c := next()
+		for i, x := range block {
+			c[i] = SumMod(x, c[i], n)
+		}
+		return c
+	}, nil
+}
+
+// Decrypter implements Mode
+func (m CTR) Decrypter(key *Matrix, n int) (BlockFunc, error) {
+	next, err := m.keystream(key, n)
+	if err != nil {
+		return nil, err
+	}
+	return func(block []int) []int {
+		p := next()
+		for i, x := range block {
+			p[i] = Residue(Residue(x, n)-p[i], n)
+		}
+		return p
+	}, nil
+}
+
+// Progressive encrypts the i-th block (starting at 1) with the key power K^i mod n, so every
+// block uses a different key. Decryption uses the powers of the inverted key.
+type Progressive struct{}
+
+// Encrypter implements Mode
+func (Progressive) Encrypter(key *Matrix, n int) (BlockFunc, error) {
+	current := key
+	return func(block []int) []int {
+		c, _ := current.VectorProductMod(n, block...) // Neglect error since block has key's order
+		current = mulMod(current, key, n)
+		return c
+	}, nil
+}
+
+// Decrypter implements Mode
+func (Progressive) Decrypter(key *Matrix, n int) (BlockFunc, error) {
+	inverse, err := key.InverseMod(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invert key; %w", err)
+	}
+	return Progressive{}.inverseDecrypter(inverse, n)
+}
+
+// inverseDecrypter implements inverseDecrypter
+func (Progressive) inverseDecrypter(inverse *Matrix, n int) (BlockFunc, error) {
+	return Progressive{}.Encrypter(inverse, n)
+}
@@ -0,0 +1,76 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestModes verify modes of operation against known cipher texts and encryption/decryption symmetry
+func TestModes(t *testing.T) {
+	spanish := "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"
+	tests := []struct {
+		name, alphabet, key, msg string
+		mode                     Mode
+		wantCipherText           string // Skipped if empty
+	}{
+		{name: "ECB", alphabet: spanish, key: "IKEY", msg: "AAAA", mode: ECB{}, wantCipherText: "AAAA"},
+		{name: "CBC", alphabet: spanish, key: "IKEY", msg: "AAAA", mode: CBC{IV: []int{1, 2}}, wantCipherText: "BAIE"},
+		{name: "CBC negative IV", alphabet: spanish, key: "IKEY", msg: "AAAA", mode: CBC{IV: []int{-26, 29}}, wantCipherText: "BAIE"},
+		{name: "CTR with carry", alphabet: spanish, key: "IKEY", msg: "AAAA", mode: CTR{Nonce: []int{0, 26}}, wantCipherText: "QCIE"},
+		{name: "progressive", alphabet: spanish, key: "IKEY", msg: "BABA", mode: Progressive{}, wantCipherText: "IEWX"},
+		{name: "CBC order 3", alphabet: spanish, key: "FORTALEZA", msg: strings.Repeat("ÑANDU", 30), mode: CBC{IV: []int{5, 0, 26}}},
+		{name: "CTR order 3", alphabet: spanish, key: "FORTALEZA", msg: strings.Repeat("ÑANDU", 30), mode: CTR{Nonce: []int{26, 26, 25}}},
+		{name: "progressive order 3", alphabet: spanish, key: "FORTALEZA", msg: strings.Repeat("ÑANDU", 30), mode: Progressive{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(NewAlphabet(test.alphabet), WithMode(test.mode))
+			cipherText, err := cipher.Encrypt(test.msg, test.key)
+			if err != nil {
+				t.Fatalf("Encrypt(msg:%q, key:%q) returned unexpected error; %v", test.msg, test.key, err)
+			}
+			if test.wantCipherText != "" && cipherText != test.wantCipherText {
+				t.Errorf("Encrypt(msg:%q, key:%q) = %q, want %q", test.msg, test.key, cipherText, test.wantCipherText)
+			}
+			plainText, err := cipher.Decrypt(cipherText, test.key)
+			if err != nil {
+				t.Fatalf("Decrypt(msg:%q, key:%q) returned unexpected error; %v", cipherText, test.key, err)
+			}
+			if plainText != test.msg {
+				t.Errorf("Decrypt(Encrypt(%q)) = %q, want original message", test.msg, plainText)
+			}
+		})
+	}
+}
+
+// TestModes_RepeatedBlocks verify chaining modes hide repeated plain text blocks
+func TestModes_RepeatedBlocks(t *testing.T) {
+	for _, mode := range []Mode{CBC{IV: []int{1, 2}}, CTR{Nonce: []int{3, 4}}, Progressive{}} {
+		cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"), WithMode(mode))
+		cipherText, _ := cipher.Encrypt("HIHI", "IKEY")
+		if blocks := []rune(cipherText); string(blocks[:2]) == string(blocks[2:]) {
+			t.Errorf("Encrypt(HIHI) using %T = %q, repeated blocks produced repeated cipher text", mode, cipherText)
+		}
+	}
+}
+
+// TestModes_Error verify modes validations
+func TestModes_Error(t *testing.T) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	for _, mode := range []Mode{CBC{}, CBC{IV: []int{1, 2, 3}}, CTR{}, CTR{Nonce: []int{1}}} {
+		cipher, _ := NewCipher(alphabet, WithMode(mode))
+		if _, err := cipher.Encrypt("AAAA", "IKEY"); err == nil {
+			t.Errorf("Encrypt() using %#v returned nil error, want non-nil", mode)
+		}
+		if _, err := cipher.Decrypt("AAAA", "IKEY"); err == nil {
+			t.Errorf("Decrypt() using %#v returned nil error, want non-nil", mode)
+		}
+	}
+
+	singular, _ := NewMatrix(2, []int{2, 0, 0, 1})
+	for _, mode := range []Mode{ECB{}, CBC{IV: []int{1, 2}}, Progressive{}} {
+		if _, err := mode.Decrypter(singular, 26); err == nil {
+			t.Errorf("Decrypter() using %#v and singular key returned nil error, want non-nil", mode)
+		}
+	}
+}
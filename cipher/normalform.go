@@ -0,0 +1,370 @@
+package cipher
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// SmithForm is the Smith normal form of a matrix A, i.e. D = U·A·V where U and V are invertible
+// and D is diagonal with every diagonal entry dividing the next one. Over the integers U and V
+// are unimodular and the diagonal entries are non-negative, modulo n they are invertible mod n
+// and the diagonal entries are divisors of n (written 0 instead of n).
+type SmithForm struct {
+	D, U, V *Matrix
+}
+
+// ElementaryDivisors returns the diagonal entries of D, also known as invariant factors. They
+// don't depend on U and V, so they tell which keys are weak: a matrix is invertible if and only
+// if all of them are 1.
+func (f *SmithForm) ElementaryDivisors() []int {
+	divisors := make([]int, f.D.order)
+	for i := range divisors {
+		divisors[i] = f.D.data[i][i]
+	}
+	return divisors
+}
+
+// Rank returns the number of non-zero diagonal entries of D. Modulo n, A·x takes
+// n^Rank / (d_1·...·d_Rank) distinct values, so messages can't be uniquely decrypted unless
+// Rank equals the order and every d_i is 1.
+func (f *SmithForm) Rank() int {
+	rank := 0
+	for _, d := range f.ElementaryDivisors() {
+		if d != 0 {
+			rank++
+		}
+	}
+	return rank
+}
+
+// HermiteForm is the Hermite normal form of a matrix A, i.e. H = U·A where U is invertible and H
+// is upper triangular in row echelon form. Over the integers U is unimodular, pivots are positive
+// and the entries above each pivot are reduced modulo it. Modulo n, U is invertible mod n, pivots
+// are divisors of n and the entries above them are reduced the same way, though for composite n
+// the form is not unique.
+type HermiteForm struct {
+	H, U *Matrix
+}
+
+// Rank returns the number of non-zero rows of H.
+func (f *HermiteForm) Rank() int {
+	rank := 0
+	for _, row := range f.H.data {
+		for _, x := range row {
+			if x != 0 {
+				rank++
+				break
+			}
+		}
+	}
+	return rank
+}
+
+// SmithNormalForm returns the Smith normal form of the matrix over the integers. Computations
+// are done in arbitrary precision, returns an error if an entry of D, U or V overflows int.
+func (m *Matrix) SmithNormalForm() (*SmithForm, error) {
+	r := newReduction(m, 0, true)
+	r.smith()
+	return r.smithForm()
+}
+
+// SmithNormalFormMod returns the Smith normal form of the matrix modulo n.
+func (m *Matrix) SmithNormalFormMod(n int) (*SmithForm, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	r := newReduction(m, n, true)
+	r.smith()
+	return r.smithForm()
+}
+
+// HermiteNormalForm returns the Hermite normal form of the matrix over the integers. Computations
+// are done in arbitrary precision, returns an error if an entry of H or U overflows int.
+func (m *Matrix) HermiteNormalForm() (*HermiteForm, error) {
+	r := newReduction(m, 0, false)
+	r.hermite()
+	return r.hermiteForm()
+}
+
+// HermiteNormalFormMod returns the Hermite normal form of the matrix modulo n.
+func (m *Matrix) HermiteNormalFormMod(n int) (*HermiteForm, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	r := newReduction(m, n, false)
+	r.hermite()
+	return r.hermiteForm()
+}
+
+// reduction holds a matrix in arbitrary precision, reduced modulo n unless n is nil, and records
+// the row operations applied to it in u and the column operations in v.
+type reduction struct {
+	order   int
+	a, u, v [][]*big.Int
+	n       *big.Int
+}
+
+// newReduction returns the reduction of m modulo n, or over the integers if n is 0. Column
+// operations are only recorded if withCols is true.
+func newReduction(m *Matrix, n int, withCols bool) *reduction {
+	r := &reduction{order: m.order, a: make([][]*big.Int, m.order), u: bigIdentity(m.order)}
+	if n != 0 {
+		r.n = big.NewInt(int64(n))
+	}
+	if withCols {
+		r.v = bigIdentity(m.order)
+	}
+	for i, row := range m.data {
+		r.a[i] = make([]*big.Int, m.order)
+		for j, x := range row {
+			r.a[i][j] = r.reduce(big.NewInt(int64(x)))
+		}
+	}
+	return r
+}
+
+// smith reduces a to its Smith normal form. Alternating Hermite normal forms of the rows and
+// the columns makes a diagonal, then each pair of diagonal entries a, b is replaced by gcd(a, b)
+// and lcm(a, b) until every entry divides the next one. The entries of a, u and v are reduced
+// in every Hermite normal form, which keeps them small.
+func (r *reduction) smith() {
+	transposed := false
+	for r.hermite(); !r.diagonal(); r.hermite() {
+		r.transpose()
+		transposed = !transposed
+	}
+	if transposed {
+		r.transpose()
+	}
+	for i := 0; i < r.order && r.a[i][i].Sign() != 0; i++ {
+		for j := i + 1; j < r.order; j++ {
+			x, y, p, q := bezout(r.a[i][i], r.a[j][j])
+			if x == nil {
+				continue
+			}
+			// diag(a, b) becomes diag(g, a·b/g) through [[x, y], [-b/g, a/g]] on the rows and
+			// [[1, -y·b/g], [1, x·a/g]] on the columns, both of determinant 1
+			r.combineRows(i, j, x, y, p, q)
+			r.combineCols(i, j, big.NewInt(1), big.NewInt(1), new(big.Int).Mul(x, p), new(big.Int).Mul(y, q))
+		}
+	}
+}
+
+// hermite reduces a to its Hermite normal form through row operations.
+func (r *reduction) hermite() {
+	t := 0
+	for j := 0; j < r.order && t < r.order; j++ {
+		pi := r.smallest(t, j)
+		if pi < 0 {
+			continue
+		}
+		r.swapRows(t, pi)
+		r.clearCol(t, j)
+		r.normalize(t, j)
+		for i := 0; i < t; i++ {
+			r.addRow(i, t, new(big.Int).Div(r.a[i][j], r.a[t][j]))
+		}
+		t++
+	}
+}
+
+// smallest returns the row from t of the non-zero entry of least absolute value in column j, or
+// -1 if they are all zero.
+func (r *reduction) smallest(t, j int) int {
+	pi := -1
+	for i := t; i < r.order; i++ {
+		if r.a[i][j].Sign() != 0 && (pi < 0 || r.a[i][j].CmpAbs(r.a[pi][j]) < 0) {
+			pi = i
+		}
+	}
+	return pi
+}
+
+// clearCol zeroes the entries below the pivot at (t, j) through row operations, which leaves
+// the gcd of the column as pivot.
+func (r *reduction) clearCol(t, j int) {
+	for i := t + 1; i < r.order; i++ {
+		if r.a[i][j].Sign() == 0 {
+			continue
+		}
+		x, y, p, q := bezout(r.a[t][j], r.a[i][j])
+		if x == nil {
+			r.addRow(i, t, q)
+			continue
+		}
+		r.combineRows(t, i, x, y, p, q)
+	}
+}
+
+// diagonal returns whether every entry of a off the diagonal is zero.
+func (r *reduction) diagonal() bool {
+	for i, row := range r.a {
+		for j, x := range row {
+			if i != j && x.Sign() != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// transpose replaces a by its transpose, so row operations act on the columns of the original
+// matrix. Since a = u·A·v, the transpose is v^T·A^T·u^T, so u and v are transposed and swapped.
+func (r *reduction) transpose() {
+	r.a = transposed(r.a)
+	r.u, r.v = transposed(r.v), transposed(r.u)
+}
+
+// bezout returns x, y, a/g and b/g for g = gcd(a, b) = x·a + y·b. If a divides b it returns
+// nil, nil, nil and b/a instead, so b can be cleared by subtracting a multiple.
+func bezout(a, b *big.Int) (x, y, p, q *big.Int) {
+	if rem := new(big.Int).Rem(b, a); rem.Sign() == 0 {
+		return nil, nil, nil, new(big.Int).Quo(b, a)
+	}
+	x, y = new(big.Int), new(big.Int)
+	g := new(big.Int).GCD(x, y, a, b)
+	return x, y, new(big.Int).Quo(a, g), new(big.Int).Quo(b, g)
+}
+
+// combineRows replaces rows s and i by x·row s + y·row i and p·row i - q·row s, a transformation
+// of determinant x·p + y·q = 1.
+func (r *reduction) combineRows(s, i int, x, y, p, q *big.Int) {
+	for _, m := range [][][]*big.Int{r.a, r.u} {
+		for k := range m[s] {
+			m[s][k], m[i][k] = r.combine(m[s][k], m[i][k], x, y, p, q)
+		}
+	}
+}
+
+// combineCols replaces columns s and k by x·col s + y·col k and p·col k - q·col s, a
+// transformation of determinant x·p + y·q = 1.
+func (r *reduction) combineCols(s, k int, x, y, p, q *big.Int) {
+	for _, m := range [][][]*big.Int{r.a, r.v} {
+		for _, row := range m {
+			row[s], row[k] = r.combine(row[s], row[k], x, y, p, q)
+		}
+	}
+}
+
+// combine returns x·a + y·b and p·b - q·a.
+func (r *reduction) combine(a, b, x, y, p, q *big.Int) (*big.Int, *big.Int) {
+	first := new(big.Int).Add(new(big.Int).Mul(x, a), new(big.Int).Mul(y, b))
+	second := new(big.Int).Sub(new(big.Int).Mul(p, b), new(big.Int).Mul(q, a))
+	return r.reduce(first), r.reduce(second)
+}
+
+// normalize multiplies row t by a unit so the pivot at (t, j) is positive over the integers or
+// a divisor of n modulo n.
+func (r *reduction) normalize(t, j int) {
+	if r.n == nil {
+		if r.a[t][j].Sign() < 0 {
+			r.scaleRow(t, big.NewInt(-1))
+		}
+		return
+	}
+	// Every residue a is g·w for g = gcd(a, n) and some unit w, whose inverse is a unit that is
+	// (a/g)^-1 modulo n/g
+	n, a := int(r.n.Int64()), int(r.a[t][j].Int64())
+	_, _, g := EGCD(a, n)
+	step := n / g
+	w, _ := ModularInverse((a/g)%step, step) // Neglect error since a/g and n/g are coprime
+	for !IsModUnit(w, n) {
+		w += step
+	}
+	r.scaleRow(t, big.NewInt(int64(w)))
+}
+
+// addRow computes row dst = row dst - q·row src.
+func (r *reduction) addRow(dst, src int, q *big.Int) {
+	for _, m := range [][][]*big.Int{r.a, r.u} {
+		for k := range m[dst] {
+			m[dst][k] = r.reduce(new(big.Int).Sub(m[dst][k], new(big.Int).Mul(q, m[src][k])))
+		}
+	}
+}
+
+// scaleRow multiplies row i by the unit w.
+func (r *reduction) scaleRow(i int, w *big.Int) {
+	for _, m := range [][][]*big.Int{r.a, r.u} {
+		for k, x := range m[i] {
+			m[i][k] = r.reduce(new(big.Int).Mul(w, x))
+		}
+	}
+}
+
+// swapRows swaps rows i and j.
+func (r *reduction) swapRows(i, j int) {
+	r.a[i], r.a[j] = r.a[j], r.a[i]
+	r.u[i], r.u[j] = r.u[j], r.u[i]
+}
+
+// reduce returns x modulo n, or x if there's no modulo.
+func (r *reduction) reduce(x *big.Int) *big.Int {
+	if r.n == nil {
+		return x
+	}
+	return x.Mod(x, r.n)
+}
+
+// smithForm returns the reduced matrix and transformations as a SmithForm.
+func (r *reduction) smithForm() (*SmithForm, error) {
+	m, err := smallMatrices(r.a, r.u, r.v)
+	if err != nil {
+		return nil, err
+	}
+	return &SmithForm{D: m[0], U: m[1], V: m[2]}, nil
+}
+
+// hermiteForm returns the reduced matrix and transformation as a HermiteForm.
+func (r *reduction) hermiteForm() (*HermiteForm, error) {
+	m, err := smallMatrices(r.a, r.u)
+	if err != nil {
+		return nil, err
+	}
+	return &HermiteForm{H: m[0], U: m[1]}, nil
+}
+
+// transposed returns the transpose of the arbitrary precision matrix a.
+func transposed(a [][]*big.Int) [][]*big.Int {
+	t := make([][]*big.Int, len(a))
+	for i := range t {
+		t[i] = make([]*big.Int, len(a))
+		for j := range t[i] {
+			t[i][j] = a[j][i]
+		}
+	}
+	return t
+}
+
+// bigIdentity returns the identity matrix of the given order in arbitrary precision.
+func bigIdentity(order int) [][]*big.Int {
+	id := make([][]*big.Int, order)
+	for i := range id {
+		id[i] = make([]*big.Int, order)
+		for j := range id[i] {
+			id[i][j] = new(big.Int)
+		}
+		id[i][i].SetInt64(1)
+	}
+	return id
+}
+
+// smallMatrices returns the arbitrary precision matrices as Matrix values, or an error if any
+// entry overflows int.
+func smallMatrices(matrices ...[][]*big.Int) ([]*Matrix, error) {
+	small := make([]*Matrix, len(matrices))
+	for k, a := range matrices {
+		m := &Matrix{order: len(a), data: make([][]int, len(a))}
+		for i, row := range a {
+			m.data[i] = make([]int, len(row))
+			for j, x := range row {
+				if !x.IsInt64() || int64(int(x.Int64())) != x.Int64() {
+					return nil, fmt.Errorf("entry %s overflows int", x)
+				}
+				m.data[i][j] = int(x.Int64())
+			}
+		}
+		small[k] = m
+	}
+	return small, nil
+}
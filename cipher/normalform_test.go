@@ -0,0 +1,332 @@
+package cipher
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	cmp "github.com/google/go-cmp/cmp"
+)
+
+// TestSmithNormalForm verify elementary divisors and rank of known matrices
+func TestSmithNormalForm(t *testing.T) {
+	tests := []struct {
+		name         string
+		matrix       *Matrix
+		n            int // 0 over the integers
+		wantDivisors []int
+		wantRank     int
+	}{
+		{
+			name:         "integers",
+			matrix:       &Matrix{order: 3, data: [][]int{{2, 4, 4}, {-6, 6, 12}, {10, -4, -16}}},
+			wantDivisors: []int{2, 6, 12},
+			wantRank:     3,
+		},
+		{
+			name:         "singular over the integers",
+			matrix:       &Matrix{order: 2, data: [][]int{{2, 4}, {3, 6}}},
+			wantDivisors: []int{1, 0},
+			wantRank:     1,
+		},
+		{
+			name:         "zero",
+			matrix:       &Matrix{order: 2, data: [][]int{{0, 0}, {0, 0}}},
+			wantDivisors: []int{0, 0},
+			wantRank:     0,
+		},
+		{
+			name:         "invertible key",
+			matrix:       &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}},
+			n:            26,
+			wantDivisors: []int{1, 1},
+			wantRank:     2,
+		},
+		{
+			name:         "weak key mod 2",
+			matrix:       &Matrix{order: 2, data: [][]int{{2, 0}, {0, 1}}},
+			n:            26,
+			wantDivisors: []int{1, 2},
+			wantRank:     2,
+		},
+		{
+			name:         "rank deficient mod 26",
+			matrix:       &Matrix{order: 2, data: [][]int{{13, 0}, {0, 2}}},
+			n:            26,
+			wantDivisors: []int{1, 0},
+			wantRank:     1,
+		},
+		{
+			name:         "unit multiples of divisors",
+			matrix:       &Matrix{order: 2, data: [][]int{{21, 0}, {0, 0}}},
+			n:            27,
+			wantDivisors: []int{3, 0},
+			wantRank:     1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var f *SmithForm
+			var err error
+			if test.n == 0 {
+				f, err = test.matrix.SmithNormalForm()
+			} else {
+				f, err = test.matrix.SmithNormalFormMod(test.n)
+			}
+			if err != nil {
+				t.Fatalf("returned unexpected error; %v", err)
+			}
+			if diff := cmp.Diff(test.wantDivisors, f.ElementaryDivisors()); diff != "" {
+				t.Errorf("ElementaryDivisors() mismatch (-want +got):\n%s", diff)
+			}
+			if got := f.Rank(); got != test.wantRank {
+				t.Errorf("Rank() = %d, want %d", got, test.wantRank)
+			}
+		})
+	}
+}
+
+// TestSmithNormalForm_Random verify D = U·A·V is a Smith normal form for random matrices
+func TestSmithNormalForm_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(31))
+	for order := 1; order <= 6; order++ {
+		for trial := 0; trial < 20; trial++ {
+			m := randomMatrix(rnd, order, 27)
+			t.Run(fmt.Sprintf("integers %v", m.data), func(t *testing.T) {
+				f, err := m.SmithNormalForm()
+				if err != nil {
+					t.Fatalf("SmithNormalForm() returned unexpected error; %v", err)
+				}
+				if got := intProduct(intProduct(f.U, m), f.V); !got.Equal(f.D) {
+					t.Errorf("U·A·V =\n%s, want D =\n%s", got, f.D)
+				}
+				verifyUnimodular(t, f.U)
+				verifyUnimodular(t, f.V)
+				verifyDivisorChain(t, f, 0)
+			})
+			for _, n := range []int{2, 12, 26, 27} {
+				t.Run(fmt.Sprintf("mod %d %v", n, m.data), func(t *testing.T) {
+					f, err := m.SmithNormalFormMod(n)
+					if err != nil {
+						t.Fatalf("SmithNormalFormMod(%d) returned unexpected error; %v", n, err)
+					}
+					if got := mulMod(mulMod(f.U, m, n), f.V, n); !got.Equal(f.D) {
+						t.Errorf("U·A·V mod %d =\n%s, want D =\n%s", n, got, f.D)
+					}
+					if !f.U.IsInvertibleMod(n) || !f.V.IsInvertibleMod(n) {
+						t.Errorf("got U =\n%s and V =\n%s, want both invertible mod %d", f.U, f.V, n)
+					}
+					verifyDivisorChain(t, f, n)
+				})
+			}
+		}
+	}
+}
+
+// TestHermiteNormalForm_Random verify H = U·A is a Hermite normal form for random matrices
+func TestHermiteNormalForm_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	for order := 1; order <= 4; order++ {
+		for trial := 0; trial < 20; trial++ {
+			m := randomMatrix(rnd, order, 10)
+			t.Run(fmt.Sprintf("integers %v", m.data), func(t *testing.T) {
+				f, err := m.HermiteNormalForm()
+				if err != nil {
+					t.Fatalf("HermiteNormalForm() returned unexpected error; %v", err)
+				}
+				if got := intProduct(f.U, m); !got.Equal(f.H) {
+					t.Errorf("U·A =\n%s, want H =\n%s", got, f.H)
+				}
+				verifyUnimodular(t, f.U)
+				verifyEchelon(t, f, 0)
+			})
+			for _, n := range []int{2, 12, 26, 27} {
+				t.Run(fmt.Sprintf("mod %d %v", n, m.data), func(t *testing.T) {
+					f, err := m.HermiteNormalFormMod(n)
+					if err != nil {
+						t.Fatalf("HermiteNormalFormMod(%d) returned unexpected error; %v", n, err)
+					}
+					if got := mulMod(f.U, m, n); !got.Equal(f.H) {
+						t.Errorf("U·A mod %d =\n%s, want H =\n%s", n, got, f.H)
+					}
+					if !f.U.IsInvertibleMod(n) {
+						t.Errorf("got U =\n%s, want invertible mod %d", f.U, n)
+					}
+					verifyEchelon(t, f, n)
+				})
+			}
+		}
+	}
+}
+
+// TestHermiteNormalForm verify the form and rank of known matrices
+func TestHermiteNormalForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   *Matrix
+		n        int // 0 over the integers
+		wantH    *Matrix
+		wantRank int
+	}{
+		{
+			name:     "integers",
+			matrix:   &Matrix{order: 3, data: [][]int{{3, 3, 1}, {0, 1, 0}, {0, 0, 19}}},
+			wantH:    &Matrix{order: 3, data: [][]int{{3, 0, 1}, {0, 1, 0}, {0, 0, 19}}},
+			wantRank: 3,
+		},
+		{
+			name:     "singular over the integers",
+			matrix:   &Matrix{order: 2, data: [][]int{{2, 4}, {3, 6}}},
+			wantH:    &Matrix{order: 2, data: [][]int{{1, 2}, {0, 0}}},
+			wantRank: 1,
+		},
+		{
+			name:     "zero column",
+			matrix:   &Matrix{order: 2, data: [][]int{{0, 4}, {0, 6}}},
+			wantH:    &Matrix{order: 2, data: [][]int{{0, 2}, {0, 0}}},
+			wantRank: 1,
+		},
+		{
+			name:     "invertible key",
+			matrix:   &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}},
+			n:        26,
+			wantH:    &Matrix{order: 2, data: [][]int{{1, 0}, {0, 1}}},
+			wantRank: 2,
+		},
+		{
+			name:     "rank deficient mod 26",
+			matrix:   &Matrix{order: 2, data: [][]int{{13, 13}, {0, 0}}},
+			n:        26,
+			wantH:    &Matrix{order: 2, data: [][]int{{13, 13}, {0, 0}}},
+			wantRank: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var f *HermiteForm
+			var err error
+			if test.n == 0 {
+				f, err = test.matrix.HermiteNormalForm()
+			} else {
+				f, err = test.matrix.HermiteNormalFormMod(test.n)
+			}
+			if err != nil {
+				t.Fatalf("returned unexpected error; %v", err)
+			}
+			if !f.H.Equal(test.wantH) {
+				t.Errorf("got H =\n%s, want\n%s", f.H, test.wantH)
+			}
+			if got := f.Rank(); got != test.wantRank {
+				t.Errorf("Rank() = %d, want %d", got, test.wantRank)
+			}
+		})
+	}
+}
+
+// TestNormalForm_Error verify invalid modulo and overflows are rejected
+func TestNormalForm_Error(t *testing.T) {
+	m := &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}
+	// The determinant of the matrix, which is the product of the elementary divisors, overflows int
+	large := &Matrix{order: 3, data: [][]int{
+		{1 << 61, 3, 5},
+		{7, 1 << 61, 11},
+		{13, 17, 1 << 61},
+	}}
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{name: "smith mod 1", op: func() error { _, err := m.SmithNormalFormMod(1); return err }},
+		{name: "hermite mod 1", op: func() error { _, err := m.HermiteNormalFormMod(1); return err }},
+		{name: "smith overflow", op: func() error { _, err := large.SmithNormalForm(); return err }},
+		{name: "hermite overflow", op: func() error { _, err := large.HermiteNormalForm(); return err }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.op(); err == nil {
+				t.Errorf("returned nil error, want non-nil")
+			}
+		})
+	}
+}
+
+// randomMatrix returns a matrix with entries in (-max, max).
+func randomMatrix(rnd *rand.Rand, order, max int) *Matrix {
+	data := make([]int, order*order)
+	for i := range data {
+		data[i] = rnd.Intn(2*max-1) - max + 1
+	}
+	m, _ := NewMatrix(order, data) // Neglect error since size is exact
+	return m
+}
+
+// intProduct returns a·b over the integers. Assumes entries are small enough not to overflow.
+func intProduct(a, b *Matrix) *Matrix {
+	p := &Matrix{order: a.order, data: make([][]int, a.order)}
+	for i := range p.data {
+		p.data[i] = make([]int, a.order)
+		for j := range p.data[i] {
+			for k := 0; k < a.order; k++ {
+				p.data[i][j] += a.data[i][k] * b.data[k][j]
+			}
+		}
+	}
+	return p
+}
+
+// verifyUnimodular fails the test if det(u) is not 1 or -1.
+func verifyUnimodular(t *testing.T, u *Matrix) {
+	t.Helper()
+	if det, _ := u.Determinant(); det != 1 && det != -1 {
+		t.Errorf("got det =  %d of\n%s, want 1 or -1", det, u)
+	}
+}
+
+// verifyDivisorChain fails the test if D is not diagonal with each entry dividing the next one,
+// and dividing n unless n is 0.
+func verifyDivisorChain(t *testing.T, f *SmithForm, n int) {
+	t.Helper()
+	for i, row := range f.D.data {
+		for j, x := range row {
+			if i != j && x != 0 {
+				t.Fatalf("got D =\n%s, want diagonal", f.D)
+			}
+		}
+	}
+	divisors := append(f.ElementaryDivisors(), n)
+	for i, d := range divisors {
+		if d == 0 {
+			divisors[i] = n // Modulo n, 0 stands for n
+		}
+	}
+	for i, d := range divisors[:f.D.order] {
+		if d < 0 || (d == 0 && divisors[i+1] != 0) || (d != 0 && divisors[i+1]%d != 0) {
+			t.Fatalf("got elementary divisors %v, want a divisor chain of %d", f.ElementaryDivisors(), n)
+		}
+	}
+}
+
+// verifyEchelon fails the test if H is not in Hermite normal form.
+func verifyEchelon(t *testing.T, f *HermiteForm, n int) {
+	t.Helper()
+	last := -1 // Column of the last pivot
+	for i, row := range f.H.data {
+		j := 0
+		for j < len(row) && row[j] == 0 {
+			j++
+		}
+		if j == len(row) {
+			last = len(row)
+			continue
+		}
+		if j <= last || row[j] < 0 || (n != 0 && n%row[j] != 0) {
+			t.Fatalf("got H =\n%s, want echelon form with positive pivots", f.H)
+		}
+		for k := 0; k < i; k++ {
+			if x := f.H.data[k][j]; x < 0 || x >= row[j] {
+				t.Fatalf("got H =\n%s, want entries above pivots reduced modulo them", f.H)
+			}
+		}
+		last = j
+	}
+}
@@ -0,0 +1,150 @@
+package cipher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnknownPolicy defines what a Normalizer does with symbols that don't belong to the alphabet
+type UnknownPolicy uint8
+
+const (
+	// RejectUnknown makes encryption and decryption fail on unknown symbols
+	RejectUnknown UnknownPolicy = iota
+	// DropUnknown removes unknown symbols from the message
+	DropUnknown
+	// KeepUnknown passes unknown symbols through unencrypted, at their original position
+	KeepUnknown
+)
+
+// Normalizer maps the symbols of a message that don't belong to the alphabet into symbols that do,
+// so real-world texts can be used without manual cleanup. Symbols that belong to the alphabet are
+// never changed, e.g. Ñ is kept in the Spanish alphabet even if diacritics are stripped.
+type Normalizer struct {
+	// FoldCase maps symbols to their upper, lower or title case variant in the alphabet.
+	FoldCase bool
+	// StripDiacritics removes diacritical marks from symbols (á becomes a) using the canonical
+	// decomposition (NFD) of Unicode. Combining marks on their own are dropped.
+	StripDiacritics bool
+	// Unknown is the policy for symbols that don't belong to the alphabet after normalization.
+	Unknown UnknownPolicy
+}
+
+// WithNormalizer makes the cipher normalize messages before encryption and decryption.
+func WithNormalizer(n Normalizer) Option {
+	return func(c *Cipher) {
+		c.normalizer = &n
+	}
+}
+
+// passThrough is an unknown symbol kept at the given position of a message.
+type passThrough struct {
+	pos    int
+	symbol rune
+}
+
+// variants returns the case variants of r, including itself.
+func variants(r rune) []rune {
+	return []rune{r, unicode.ToUpper(r), unicode.ToLower(r), unicode.ToTitle(r)}
+}
+
+// strippedMark returns whether r is a combining mark removed by the cipher's normalizer.
+func (c *Cipher) strippedMark(r rune) bool {
+	return c.normalizer.StripDiacritics && unicode.Is(unicode.Mn, r) && !c.alphabet.Contains(r)
+}
+
+// normalizeSymbol returns the symbol of the alphabet that r is mapped into, or false if r is
+// unknown.
+func (c *Cipher) normalizeSymbol(r rune) (rune, bool) {
+	n := c.normalizer
+	candidates := []rune{r}
+	if n.FoldCase {
+		candidates = variants(r)
+	}
+	if n.StripDiacritics {
+		base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+		if n.FoldCase {
+			candidates = append(candidates, variants(base)...)
+		} else {
+			candidates = append(candidates, base)
+		}
+	}
+	for _, s := range candidates {
+		if c.alphabet.Contains(s) {
+			return s, true
+		}
+	}
+	return r, false
+}
+
+// normalize returns the symbols of rawM that belong to the alphabet after applying the cipher's
+// normalizer, and the unknown symbols kept by the normalizer policy. Returns an error if rawM has
+// symbols that don't belong to the alphabet and the policy rejects them.
+func (c *Cipher) normalize(rawM string) ([]rune, []passThrough, error) {
+	if c.normalizer == nil {
+		if err := c.alphabet.unknownSymbol(rawM); err != nil {
+			return nil, nil, fmt.Errorf("message %q does not belong to alphabet %q; %w", rawM, c.alphabet, err)
+		}
+		return []rune(rawM), nil, nil
+	}
+	// Compose symbols first, so decomposed sequences like N + combining tilde are matched as Ñ.
+	// Each segment of the composed text comes from a range of rawM, so unknown symbols are reported
+	// at their position in rawM: a composed symbol at the position of its first rune.
+	var it norm.Iter
+	it.InitString(norm.NFC, rawM)
+	msg := make([]rune, 0, len(rawM))
+	var kept []passThrough
+	pos, raw := 0, 0
+	for !it.Done() {
+		start := it.Pos()
+		segment := []rune(string(it.Next()))
+		size := utf8.RuneCountInString(rawM[start:it.Pos()])
+		for k, r := range segment {
+			if c.strippedMark(r) {
+				continue
+			}
+			s, found := c.normalizeSymbol(r)
+			switch {
+			case found:
+				msg = append(msg, s)
+			case c.normalizer.Unknown == DropUnknown:
+				continue
+			case c.normalizer.Unknown == KeepUnknown:
+				kept = append(kept, passThrough{pos: pos, symbol: r})
+			default:
+				if k >= size {
+					k = size - 1 // Runes that NFC expands into several map to the last rune of the segment
+				}
+				return nil, nil, fmt.Errorf("message does not belong to alphabet %q; %w", c.alphabet, &SymbolError{Symbol: r, Position: raw + k})
+			}
+			pos++
+		}
+		raw += size
+	}
+	return msg, kept, nil
+}
+
+// restore returns text with the kept symbols inserted back at their positions. If text is longer
+// than the original message (padding was added) its extra symbols are written at the end, and if
+// it is shorter (padding was removed) the remaining kept symbols are.
+func restore(text string, kept []passThrough) string {
+	if len(kept) == 0 {
+		return text
+	}
+	var b strings.Builder
+	symbols := []rune(text)
+	for pos := 0; len(symbols) > 0 || len(kept) > 0; pos++ {
+		if len(kept) > 0 && (kept[0].pos == pos || len(symbols) == 0) {
+			b.WriteRune(kept[0].symbol)
+			kept = kept[1:]
+			continue
+		}
+		b.WriteRune(symbols[0])
+		symbols = symbols[1:]
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package cipher
+
+import (
+	"testing"
+)
+
+// TestNormalizer verify messages are normalized before encryption and decryption
+func TestNormalizer(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name, msg      string
+		normalizer     Normalizer
+		opts           []Option
+		wantCipherText string // Skipped if empty
+		wantPlainText  string
+	}{
+		{
+			name:          "fold case",
+			msg:           "hola",
+			normalizer:    Normalizer{FoldCase: true},
+			wantPlainText: "HOLA",
+		},
+		{
+			name:          "strip diacritics keeps alphabet symbols",
+			msg:           "Árbol ñandú",
+			normalizer:    Normalizer{FoldCase: true, StripDiacritics: true, Unknown: DropUnknown},
+			wantPlainText: "ARBOLÑANDU",
+		},
+		{
+			name:          "strip diacritics without folding case",
+			msg:           "ÁRBOLÑANDÚ",
+			normalizer:    Normalizer{StripDiacritics: true},
+			wantPlainText: "ARBOLÑANDU",
+		},
+		{
+			name:          "decomposed sequences",
+			msg:           "N\u0303ANDUS\u0301",
+			normalizer:    Normalizer{StripDiacritics: true},
+			wantPlainText: "ÑANDUS",
+		},
+		{
+			name:          "leading combining mark",
+			msg:           "\u0301AB",
+			normalizer:    Normalizer{StripDiacritics: true},
+			wantPlainText: "AB",
+		},
+		{
+			name:          "drop unknown",
+			msg:           "HOLA, MUNDO!!",
+			normalizer:    Normalizer{Unknown: DropUnknown},
+			opts:          []Option{WithPadding(LengthPadding{})},
+			wantPlainText: "HOLAMUNDO",
+		},
+		{
+			name:           "keep unknown with padding",
+			msg:            "Hola, mundo!",
+			normalizer:     Normalizer{FoldCase: true, Unknown: KeepUnknown},
+			opts:           []Option{WithPadding(LengthPadding{})},
+			wantCipherText: "QYHQ, JGZSM!G",
+			wantPlainText:  "HOLA, MUNDO!",
+		},
+		{
+			name:           "keep unknown at the beginning",
+			msg:            "¿QUE?",
+			normalizer:     Normalizer{Unknown: KeepUnknown},
+			opts:           []Option{WithPadding(FillerPadding{Filler: 'X'})},
+			wantCipherText: "¿VZC?V",
+			wantPlainText:  "¿QUE?",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(spanish, append(test.opts, WithNormalizer(test.normalizer))...)
+			cipherText, err := cipher.Encrypt(test.msg, "IKEY")
+			if err != nil {
+				t.Fatalf("Encrypt(%q) returned unexpected error; %v", test.msg, err)
+			}
+			if test.wantCipherText != "" && cipherText != test.wantCipherText {
+				t.Errorf("Encrypt(%q) = %q, want %q", test.msg, cipherText, test.wantCipherText)
+			}
+			plainText, err := cipher.Decrypt(cipherText, "IKEY")
+			if err != nil {
+				t.Fatalf("Decrypt(%q) returned unexpected error; %v", cipherText, err)
+			}
+			if plainText != test.wantPlainText {
+				t.Errorf("Decrypt(Encrypt(%q)) = %q, want %q", test.msg, plainText, test.wantPlainText)
+			}
+		})
+	}
+}
+
+// TestNormalizer_Error verify unknown symbols are rejected by default
+func TestNormalizer_Error(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name, msg  string
+		normalizer Normalizer
+	}{
+		{name: "case not folded", msg: "hola", normalizer: Normalizer{StripDiacritics: true}},
+		{name: "diacritics not stripped", msg: "ÁRBOL", normalizer: Normalizer{FoldCase: true}},
+		{name: "unknown symbol", msg: "HOLA MUNDO", normalizer: Normalizer{FoldCase: true, StripDiacritics: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(spanish, WithNormalizer(test.normalizer))
+			if _, err := cipher.Encrypt(test.msg, "IKEY"); err == nil {
+				t.Errorf("Encrypt(%q) returned nil error, want non-nil", test.msg)
+			}
+		})
+	}
+}
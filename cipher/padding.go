@@ -0,0 +1,135 @@
+package cipher
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Padding extends messages to a multiple of the block size (key's order) and removes such
+// extension after decryption.
+type Padding interface {
+	// Pad returns msg extended to a multiple of blockSize.
+	Pad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error)
+	// Unpad returns msg without the symbols added by Pad.
+	Unpad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error)
+}
+
+// FillerPadding appends a fixed filler symbol until the message length is a multiple of the
+// block size, as done in most textbooks (e.g. "X"). Unpad strips up to blockSize-1 trailing
+// fillers, so messages that end with the filler symbol can't be recovered unambiguously.
+type FillerPadding struct {
+	Filler rune
+}
+
+// Pad implements Padding
+func (p FillerPadding) Pad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	if !alphabet.Contains(p.Filler) {
+		return nil, fmt.Errorf("filler %q does not belong to alphabet %q", p.Filler, alphabet)
+	}
+	padded := append([]rune(nil), msg...)
+	for len(padded)%blockSize != 0 {
+		padded = append(padded, p.Filler)
+	}
+	return padded, nil
+}
+
+// Unpad implements Padding
+func (p FillerPadding) Unpad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	n := len(msg)
+	for n > 0 && len(msg)-n < blockSize-1 && msg[n-1] == p.Filler {
+		n--
+	}
+	return msg[:n], nil
+}
+
+// LengthPadding is a PKCS#7-style padding. It always appends k symbols (1 <= k <= blockSize),
+// each of them being the symbol at index k-1 of the alphabet, so it can be removed unambiguously.
+// Requires the block size to be at most the alphabet size.
+type LengthPadding struct{}
+
+// Pad implements Padding
+func (p LengthPadding) Pad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	if n := len(alphabet.Symbols()); blockSize > n {
+		return nil, fmt.Errorf("block size %d is greater than alphabet size %d", blockSize, n)
+	}
+	k := blockSize - len(msg)%blockSize
+	symbol, _ := alphabet.Itos(k - 1) // Neglect error since k <= blockSize
+	padded := append([]rune(nil), msg...)
+	for i := 0; i < k; i++ {
+		padded = append(padded, symbol)
+	}
+	return padded, nil
+}
+
+// Unpad implements Padding
+func (p LengthPadding) Unpad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	if len(msg) == 0 {
+		return nil, fmt.Errorf("got empty message, want at least one padding symbol")
+	}
+	last := msg[len(msg)-1]
+	k, err := alphabet.Stoi(last)
+	if err != nil {
+		return nil, &SymbolError{Symbol: last, Position: len(msg) - 1}
+	}
+	k++
+	if k > blockSize || k > len(msg) {
+		return nil, fmt.Errorf("got invalid padding length %d", k)
+	}
+	for _, r := range msg[len(msg)-k:] {
+		if r != last {
+			return nil, fmt.Errorf("got inconsistent padding symbols %q", string(msg[len(msg)-k:]))
+		}
+	}
+	return msg[:len(msg)-k], nil
+}
+
+// RandomPadding prefixes the message with a symbol whose index is the number of filler symbols
+// k (0 <= k < blockSize), and appends k symbols read from Random, or from crypto/rand if Random is
+// nil. Random filler avoids the repeated final blocks produced by deterministic paddings.
+// Requires the block size to be at most the alphabet size.
+type RandomPadding struct {
+	Random io.Reader
+}
+
+// Pad implements Padding
+func (p RandomPadding) Pad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	if n := len(alphabet.Symbols()); blockSize > n {
+		return nil, fmt.Errorf("block size %d is greater than alphabet size %d", blockSize, n)
+	}
+	k := (blockSize - (len(msg)+1)%blockSize) % blockSize
+	prefix, _ := alphabet.Itos(k) // Neglect error since k < blockSize
+	padded := make([]rune, 0, len(msg)+k+1)
+	padded = append(padded, prefix)
+	padded = append(padded, msg...)
+	random := p.Random
+	if random == nil {
+		random = crand.Reader
+	}
+	mod := big.NewInt(int64(len(alphabet.Symbols())))
+	for i := 0; i < k; i++ {
+		x, err := crand.Int(random, mod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read random data; %v", err)
+		}
+		r, _ := alphabet.Itos(int(x.Int64())) // Neglect error since x is in [0, mod)
+		padded = append(padded, r)
+	}
+	return padded, nil
+}
+
+// Unpad implements Padding
+func (p RandomPadding) Unpad(msg []rune, blockSize int, alphabet *Alphabet) ([]rune, error) {
+	if len(msg) == 0 {
+		return nil, fmt.Errorf("got empty message, want length prefix")
+	}
+	k, err := alphabet.Stoi(msg[0])
+	if err != nil {
+		return nil, err
+	}
+	if k >= blockSize || k > len(msg)-1 {
+		return nil, fmt.Errorf("got invalid padding length %d", k)
+	}
+	return msg[1 : len(msg)-k], nil
+}
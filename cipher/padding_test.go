@@ -0,0 +1,132 @@
+package cipher
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestPadding verify padded messages are multiple of block size and are restored by Unpad
+func TestPadding(t *testing.T) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name       string
+		padding    Padding
+		msg        string
+		blockSize  int
+		wantPadded string // Empty when padding is random
+	}{
+		{name: "filler", padding: FillerPadding{Filler: 'X'}, msg: "CONSULTA", blockSize: 3, wantPadded: "CONSULTAX"},
+		{name: "filler exact length", padding: FillerPadding{Filler: 'X'}, msg: "CONSUL", blockSize: 3, wantPadded: "CONSUL"},
+		{name: "length", padding: LengthPadding{}, msg: "CONSULTA", blockSize: 3, wantPadded: "CONSULTAA"},
+		{name: "length exact length", padding: LengthPadding{}, msg: "CONSUL", blockSize: 3, wantPadded: "CONSULCCC"},
+		{name: "length empty message", padding: LengthPadding{}, msg: "", blockSize: 2, wantPadded: "BB"},
+		{name: "random", padding: RandomPadding{Random: rand.New(rand.NewSource(1))}, msg: "CONSULTA", blockSize: 3},
+		{name: "random exact length", padding: RandomPadding{Random: rand.New(rand.NewSource(1))}, msg: "CONSU", blockSize: 3, wantPadded: "ACONSU"},
+		{name: "random order 6", padding: RandomPadding{Random: rand.New(rand.NewSource(1))}, msg: "CRIPTOGRAFIA", blockSize: 6},
+		{name: "random default source", padding: RandomPadding{}, msg: "CONSULTA", blockSize: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			padded, err := test.padding.Pad([]rune(test.msg), test.blockSize, alphabet)
+			if err != nil {
+				t.Fatalf("Pad(%q, %d) returned unexpected error; %v", test.msg, test.blockSize, err)
+			}
+			if len(padded)%test.blockSize != 0 {
+				t.Errorf("Pad(%q, %d) = %q, length is not multiple of block size", test.msg, test.blockSize, string(padded))
+			}
+			if test.wantPadded != "" && string(padded) != test.wantPadded {
+				t.Errorf("Pad(%q, %d) = %q, want %q", test.msg, test.blockSize, string(padded), test.wantPadded)
+			}
+			unpadded, err := test.padding.Unpad(padded, test.blockSize, alphabet)
+			if err != nil {
+				t.Fatalf("Unpad(%q, %d) returned unexpected error; %v", string(padded), test.blockSize, err)
+			}
+			if string(unpadded) != test.msg {
+				t.Errorf("Unpad(%q, %d) = %q, want %q", string(padded), test.blockSize, string(unpadded), test.msg)
+			}
+		})
+	}
+}
+
+// TestPadding_Error verify invalid configurations and malformed padding are rejected
+func TestPadding_Error(t *testing.T) {
+	binary := NewAlphabet("01")
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name      string
+		padding   Padding
+		alphabet  *Alphabet
+		msg       string
+		blockSize int
+		unpad     bool
+	}{
+		{name: "filler not in alphabet", padding: FillerPadding{Filler: 'x'}, alphabet: spanish, msg: "A", blockSize: 2},
+		{name: "length block larger than alphabet", padding: LengthPadding{}, alphabet: binary, msg: "0", blockSize: 3},
+		{name: "random block larger than alphabet", padding: RandomPadding{Random: rand.New(rand.NewSource(1))}, alphabet: binary, msg: "0", blockSize: 4},
+		{name: "random exhausted source", padding: RandomPadding{Random: strings.NewReader("")}, alphabet: spanish, msg: "A", blockSize: 3},
+		{name: "length empty message", padding: LengthPadding{}, alphabet: spanish, blockSize: 3, unpad: true},
+		{name: "length symbol not in alphabet", padding: LengthPadding{}, alphabet: spanish, msg: "AAa", blockSize: 3, unpad: true},
+		{name: "length too long", padding: LengthPadding{}, alphabet: spanish, msg: "AAD", blockSize: 3, unpad: true},
+		{name: "length inconsistent", padding: LengthPadding{}, alphabet: spanish, msg: "ABC", blockSize: 3, unpad: true},
+		{name: "random empty message", padding: RandomPadding{}, alphabet: spanish, blockSize: 3, unpad: true},
+		{name: "random prefix not in alphabet", padding: RandomPadding{}, alphabet: spanish, msg: "aBC", blockSize: 3, unpad: true},
+		{name: "random prefix too long", padding: RandomPadding{}, alphabet: spanish, msg: "DBC", blockSize: 3, unpad: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			if test.unpad {
+				_, err = test.padding.Unpad([]rune(test.msg), test.blockSize, test.alphabet)
+			} else {
+				_, err = test.padding.Pad([]rune(test.msg), test.blockSize, test.alphabet)
+			}
+			if err == nil {
+				t.Fatalf("padding %q (block size %d) returned nil error, want non-nil", test.msg, test.blockSize)
+			}
+		})
+	}
+}
+
+// TestCipherWithPadding verify ciphers with padding accept any message length
+func TestCipherWithPadding(t *testing.T) {
+	paddings := map[string]Padding{
+		"filler": FillerPadding{Filler: 'X'},
+		"length": LengthPadding{},
+		"random": RandomPadding{Random: rand.New(rand.NewSource(1))},
+	}
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	for name, padding := range paddings {
+		for _, msg := range []string{"CONSUL", "CONSULTA", "UUNAMFCIENCIASS"} {
+			t.Run(name+" "+msg, func(t *testing.T) {
+				cipher, err := NewCipher(alphabet, WithPadding(padding))
+				if err != nil {
+					t.Fatalf("NewCipher(%q) returned unexpected error; %v", alphabet, err)
+				}
+				cipherText, err := cipher.Encrypt(msg, "FORTALEZA")
+				if err != nil {
+					t.Fatalf("Encrypt(msg:%q, key:%q) returned unexpected error; %v", msg, "FORTALEZA", err)
+				}
+				plainText, err := cipher.Decrypt(cipherText, "FORTALEZA")
+				if err != nil {
+					t.Fatalf("Decrypt(msg:%q, key:%q) returned unexpected error; %v", cipherText, "FORTALEZA", err)
+				}
+				if plainText != msg {
+					t.Errorf("Decrypt(Encrypt(%q)) = %q, want %q", msg, plainText, msg)
+				}
+			})
+		}
+	}
+}
+
+// TestCipherWithPadding_Error verify padding errors are reported by the cipher
+func TestCipherWithPadding_Error(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"), WithPadding(FillerPadding{Filler: 'x'}))
+	if _, err := cipher.Encrypt("CONSULTA", "FORTALEZA"); err == nil {
+		t.Errorf("Encrypt() with invalid filler returned nil error, want non-nil")
+	}
+	cipher, _ = NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"), WithPadding(LengthPadding{}))
+	if _, err := cipher.Decrypt("KUTÑOB", "FORTALEZA"); err == nil {
+		t.Errorf("Decrypt() of message without padding returned nil error, want non-nil")
+	}
+}
@@ -0,0 +1,175 @@
+package cipher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// latexSpecial maps the symbols that have a special meaning in LaTeX to their escaped form.
+var latexSpecial = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`, "&", `\&`, "#", `\#`, "%", `\%`,
+	"_", `\_`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`, " ", `\ `,
+)
+
+// latexText returns s as LaTeX text usable in math mode.
+func latexText(s string) string {
+	return `\text{` + latexSpecial.Replace(s) + "}"
+}
+
+// latexMatrix returns the rows as a LaTeX pmatrix.
+func latexMatrix(rows [][]int) string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		entries := make([]string, len(row))
+		for j, x := range row {
+			entries[j] = strconv.Itoa(x)
+		}
+		lines[i] = strings.Join(entries, " & ")
+	}
+	return `\begin{pmatrix}` + strings.Join(lines, ` \\ `) + `\end{pmatrix}`
+}
+
+// latexVector returns v as a LaTeX column vector.
+func latexVector(v []int) string {
+	rows := make([][]int, len(v))
+	for i, x := range v {
+		rows[i] = []int{x}
+	}
+	return latexMatrix(rows)
+}
+
+// markdownCode returns s as a Markdown code span that can be used inside tables.
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// markdownTable returns the rows as a Markdown table with the given header.
+func markdownTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat("---|", len(header)) + "\n")
+	for _, row := range rows {
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	return b.String()
+}
+
+// markdownMatrix returns the rows as a Markdown table with empty header cells, and an extra column
+// with the given header and entries if extra is not nil.
+func markdownMatrix(rows [][]int, extraHeader string, extra []int) string {
+	header := make([]string, len(rows))
+	table := make([][]string, len(rows))
+	for i, row := range rows {
+		table[i] = make([]string, len(row))
+		for j, x := range row {
+			table[i][j] = strconv.Itoa(x)
+		}
+		if extra != nil {
+			table[i] = append(table[i], strconv.Itoa(extra[i]))
+		}
+	}
+	if extra != nil {
+		header = append(header, extraHeader)
+	}
+	return markdownTable(header, table)
+}
+
+// joinInts returns the ints separated by commas.
+func joinInts(ints []int) string {
+	s := make([]string, len(ints))
+	for i, x := range ints {
+		s[i] = strconv.Itoa(x)
+	}
+	return strings.Join(s, ", ")
+}
+
+// LaTeX returns the matrix as a LaTeX pmatrix environment, to be used in math mode.
+func (m Matrix) LaTeX() string {
+	return latexMatrix(m.data)
+}
+
+// Markdown returns the matrix as a Markdown table with empty header cells.
+func (m Matrix) Markdown() string {
+	return markdownMatrix(m.data, "", nil)
+}
+
+// LaTeX returns the key as a LaTeX pmatrix environment, to be used in math mode. The translation
+// vector of affine keys is written after the matrix as a column vector.
+func (k Key) LaTeX() string {
+	if k.shift == nil {
+		return k.matrix.LaTeX()
+	}
+	return k.matrix.LaTeX() + ", b = " + latexVector(k.shift)
+}
+
+// Markdown returns the key as a Markdown table with empty header cells. The translation vector of
+// affine keys is written as an extra column with header b.
+func (k Key) Markdown() string {
+	return markdownMatrix(k.matrix.data, "b", k.shift)
+}
+
+// LaTeX returns the trace as a LaTeX align* environment with the modular equations of every step.
+func (t Trace) LaTeX() string {
+	n := strconv.Itoa(t.Modulus)
+	mod := ` \pmod{` + n + "}"
+	lines := []string{"K &= " + t.Key.LaTeX()}
+	if t.Shift != nil {
+		lines = append(lines, "b &= "+latexVector(t.Shift))
+	}
+	key := "K"
+	if t.Decryption {
+		key = "K^{-1}"
+		lines = append(lines,
+			`\det(K) &\equiv `+strconv.Itoa(t.Determinant)+mod,
+			`\det(K)^{-1} &\equiv `+strconv.Itoa(t.DeterminantInverse)+mod,
+			`\operatorname{adj}(K) &\equiv `+t.Adjoint.LaTeX()+mod,
+			`K^{-1} &\equiv `+strconv.Itoa(t.DeterminantInverse)+` \cdot \operatorname{adj}(K) \equiv `+t.Inverse.LaTeX()+mod,
+		)
+	}
+	for _, block := range t.Blocks {
+		product := key + " " + latexVector(block.Vector)
+		switch {
+		case t.Shift != nil && t.Decryption:
+			product = key + `\left(` + latexVector(block.Vector) + ` - b\right)`
+		case t.Shift != nil:
+			product += " + b"
+		}
+		lines = append(lines, fmt.Sprintf(`%s: %s &= %s \equiv %s%s \rightarrow %s`,
+			latexText(string(block.Symbols)), product, latexVector(block.Product),
+			latexVector(block.Residues), mod, latexText(string(block.Output))))
+	}
+	return "\\begin{align*}\n" + strings.Join(lines, " \\\\\n") + "\n\\end{align*}\n"
+}
+
+// Markdown returns the trace as Markdown text, with the key matrices and the transformed blocks
+// as tables.
+func (t Trace) Markdown() string {
+	var b strings.Builder
+	n := strconv.Itoa(t.Modulus)
+	operation, in, out, product := t.labels()
+	fmt.Fprintf(&b, "**%s** modulo %s with key K:\n\n%s\n", operation, n, markdownMatrix(t.Key.data, "b", t.Shift))
+	if t.Decryption {
+		fmt.Fprintf(&b, "det(K) mod %s = %d, and its inverse det(K)^-1 mod %s = %d.\n\n", n, t.Determinant, n, t.DeterminantInverse)
+		fmt.Fprintf(&b, "adj(K) mod %s:\n\n%s\n", n, t.Adjoint.Markdown())
+		fmt.Fprintf(&b, "K^-1 = det(K)^-1 · adj(K) mod %s:\n\n%s\n", n, t.Inverse.Markdown())
+	}
+	header := []string{"Block", in, in + " vector", product, product + " mod " + n, out}
+	rows := make([][]string, len(t.Blocks))
+	for i, block := range t.Blocks {
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			markdownCode(string(block.Symbols)),
+			joinInts(block.Vector),
+			joinInts(block.Product),
+			joinInts(block.Residues),
+			markdownCode(string(block.Output)),
+		}
+	}
+	fmt.Fprintf(&b, "%s\nResult: %s\n", markdownTable(header, rows), markdownCode(t.Result))
+	return b.String()
+}
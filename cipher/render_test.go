@@ -0,0 +1,121 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderMatrix verify matrices and keys are rendered as LaTeX and Markdown
+func TestRenderMatrix(t *testing.T) {
+	key, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	affineKey, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	tests := []struct {
+		name, got, want string
+	}{
+		{
+			name: "matrix latex",
+			got:  key.matrix.LaTeX(),
+			want: `\begin{pmatrix}8 & 10 \\ 4 & 25\end{pmatrix}`,
+		},
+		{
+			name: "matrix markdown",
+			got:  key.matrix.Markdown(),
+			want: "|  |  |\n|---|---|\n| 8 | 10 |\n| 4 | 25 |\n",
+		},
+		{
+			name: "key latex",
+			got:  key.LaTeX(),
+			want: `\begin{pmatrix}8 & 10 \\ 4 & 25\end{pmatrix}`,
+		},
+		{
+			name: "affine key latex",
+			got:  affineKey.LaTeX(),
+			want: `\begin{pmatrix}8 & 10 \\ 4 & 25\end{pmatrix}, b = \begin{pmatrix}1 \\ 2\end{pmatrix}`,
+		},
+		{
+			name: "key markdown",
+			got:  key.Markdown(),
+			want: "|  |  |\n|---|---|\n| 8 | 10 |\n| 4 | 25 |\n",
+		},
+		{
+			name: "affine key markdown",
+			got:  affineKey.Markdown(),
+			want: "|  |  | b |\n|---|---|---|\n| 8 | 10 | 1 |\n| 4 | 25 | 2 |\n",
+		},
+		{
+			name: "latex special symbols",
+			got:  latexText(`A B\{}$&#%_~^`),
+			want: `\text{A\ B\textbackslash{}\{\}\$\&\#\%\_\textasciitilde{}\textasciicircum{}}`,
+		},
+		{
+			name: "markdown code",
+			got:  markdownCode("A|B"),
+			want: "`A\\|B`",
+		},
+		{
+			name: "markdown code with backtick",
+			got:  markdownCode("A`B"),
+			want: "`` A`B ``",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.got != test.want {
+				t.Errorf("got \n%s, want \n%s", test.got, test.want)
+			}
+		})
+	}
+}
+
+// TestRenderTrace verify traces are rendered as LaTeX and Markdown walkthroughs
+func TestRenderTrace(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"))
+	key, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	decryption, _ := cipher.TraceDecrypt("GU", key)
+	wantLaTeX := "\\begin{align*}\n" +
+		`K &= \begin{pmatrix}8 & 10 \\ 4 & 25\end{pmatrix} \\` + "\n" +
+		`b &= \begin{pmatrix}1 \\ 2\end{pmatrix} \\` + "\n" +
+		`\det(K) &\equiv 25 \pmod{27} \\` + "\n" +
+		`\det(K)^{-1} &\equiv 13 \pmod{27} \\` + "\n" +
+		`\operatorname{adj}(K) &\equiv \begin{pmatrix}25 & 17 \\ 23 & 8\end{pmatrix} \pmod{27} \\` + "\n" +
+		`K^{-1} &\equiv 13 \cdot \operatorname{adj}(K) \equiv \begin{pmatrix}1 & 5 \\ 2 & 23\end{pmatrix} \pmod{27} \\` + "\n" +
+		`\text{GU}: K^{-1}\left(\begin{pmatrix}6 \\ 21\end{pmatrix} - b\right) &= \begin{pmatrix}100 \\ 447\end{pmatrix} \equiv \begin{pmatrix}19 \\ 15\end{pmatrix} \pmod{27} \rightarrow \text{SO}` + "\n" +
+		"\\end{align*}\n"
+	if got := decryption.LaTeX(); got != wantLaTeX {
+		t.Errorf("LaTeX() = \n%s, want \n%s", got, wantLaTeX)
+	}
+	wantMarkdown := "**Decryption** modulo 27 with key K:\n\n" +
+		"|  |  | b |\n|---|---|---|\n| 8 | 10 | 1 |\n| 4 | 25 | 2 |\n\n" +
+		"det(K) mod 27 = 25, and its inverse det(K)^-1 mod 27 = 13.\n\n" +
+		"adj(K) mod 27:\n\n|  |  |\n|---|---|\n| 25 | 17 |\n| 23 | 8 |\n\n" +
+		"K^-1 = det(K)^-1 · adj(K) mod 27:\n\n|  |  |\n|---|---|\n| 1 | 5 |\n| 2 | 23 |\n\n" +
+		"| Block | C | C vector | K^-1·(C - b) | K^-1·(C - b) mod 27 | P |\n" +
+		"|---|---|---|---|---|---|\n" +
+		"| 1 | `GU` | 6, 21 | 100, 447 | 19, 15 | `SO` |\n\n" +
+		"Result: `SO`\n"
+	if got := decryption.Markdown(); got != wantMarkdown {
+		t.Errorf("Markdown() = \n%s, want \n%s", got, wantMarkdown)
+	}
+
+	encryption, _ := cipher.TraceEncrypt("SO", key)
+	wantLine := `\text{SO}: K \begin{pmatrix}19 \\ 15\end{pmatrix} + b &= \begin{pmatrix}303 \\ 453\end{pmatrix} \equiv \begin{pmatrix}6 \\ 21\end{pmatrix} \pmod{27} \rightarrow \text{GU}`
+	if got := encryption.LaTeX(); !strings.Contains(got, wantLine) || strings.Contains(got, `\det`) {
+		t.Errorf("LaTeX() = \n%s, want the encryption equations only", got)
+	}
+	wantRow := "| 1 | `SO` | 19, 15 | 303, 453 | 6, 21 | `GU` |\n"
+	if got := encryption.Markdown(); !strings.Contains(got, wantRow) || strings.Contains(got, "det(K)") {
+		t.Errorf("Markdown() = \n%s, want the encryption table only", got)
+	}
+	linear, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	encryption, _ = cipher.TraceEncrypt("SO", linear)
+	if got, want := encryption.LaTeX(), `K \begin{pmatrix}19 \\ 15\end{pmatrix} &=`; !strings.Contains(got, want) {
+		t.Errorf("LaTeX() = \n%s, want it to contain %s", got, want)
+	}
+	if got, want := encryption.Markdown(), "| Block | P | P vector | K·P | K·P mod 27 | C |\n"; !strings.Contains(got, want) {
+		t.Errorf("Markdown() = \n%s, want it to contain %s", got, want)
+	}
+	decryption, _ = cipher.TraceDecrypt("GU", linear)
+	if got, want := decryption.Markdown(), "| Block | C | C vector | K^-1·C | K^-1·C mod 27 | P |\n"; !strings.Contains(got, want) {
+		t.Errorf("Markdown() = \n%s, want it to contain %s", got, want)
+	}
+}
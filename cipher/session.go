@@ -0,0 +1,92 @@
+package cipher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Session is a cipher bound to a single key. The key is verified and inverted once when the
+// session is created, so encrypting or decrypting a message doesn't repeat that work. Messages are
+// only transformed faster than by the cipher when it uses ECB without padding nor normalizer,
+// where every block is transformed in a single pass; otherwise the cost per message is the same.
+// Sessions are safe for concurrent use.
+type Session struct {
+	c       *Cipher
+	key     *Key
+	inverse *Matrix
+}
+
+// NewSession returns a session of the cipher for the given key. Returns an error if key is not
+// invertible by cipher's modulo.
+func (c *Cipher) NewSession(key *Key) (*Session, error) {
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	inverse, _ := key.matrix.InverseMod(c.mod) // Neglect error since key was verified
+	return &Session{c: c, key: key, inverse: inverse}, nil
+}
+
+// direct returns whether messages can be transformed in a single pass, that is, when the cipher
+// encrypts blocks independently (ECB) and has neither padding nor normalizer.
+func (s *Session) direct() bool {
+	_, ecb := s.c.blockMode().(ECB)
+	return ecb && s.c.padding == nil && s.c.normalizer == nil
+}
+
+// Encrypt plain text using the session's key. Returns an error if message doesn't belong to the
+// cipher's alphabet or if message length is not multiple of key's order (matrix order) and the
+// cipher has no padding.
+func (s *Session) Encrypt(rawM string) (string, error) {
+	if !s.direct() {
+		return s.c.encrypt(rawM, s.key)
+	}
+	return s.transform(rawM, &s.key.matrix, true)
+}
+
+// Decrypt cipher text using the session's key. Returns an error if cipher text doesn't belong to
+// the cipher's alphabet or if cipher text length is not multiple of key's order (matrix order).
+// If the cipher has padding, it is removed from the result and an error is returned when it is
+// malformed.
+func (s *Session) Decrypt(rawM string) (string, error) {
+	if !s.direct() {
+		return s.c.decrypt(rawM, s.key, s.inverse)
+	}
+	return s.transform(rawM, s.inverse, false)
+}
+
+// transform applies m to every block of rawM in a single pass, without intermediate copies of
+// the message. The translation vector of affine keys is added after encrypting a block and
+// subtracted before decrypting it.
+func (s *Session) transform(rawM string, m *Matrix, encrypt bool) (string, error) {
+	n, shift := s.c.mod, s.key.shift
+	var result strings.Builder
+	result.Grow(len(rawM))
+	block := make([]int, m.order)
+	i, pos := 0, 0
+	for _, r := range rawM {
+		x, found := s.c.alphabet.symbolIndex[r]
+		if !found {
+			return "", fmt.Errorf("message %q does not belong to alphabet %q; %w", rawM, s.c.alphabet, &SymbolError{Symbol: r, Position: pos})
+		}
+		pos++
+		if !encrypt && shift != nil {
+			x = Residue(x-shift[i], n)
+		}
+		block[i] = x
+		if i++; i < m.order {
+			continue
+		}
+		i = 0
+		product, _ := m.VectorProductMod(n, block...) // Neglect error since block has key's order
+		for row, y := range product {
+			if encrypt && shift != nil {
+				y = SumMod(y, shift[row], n)
+			}
+			result.WriteRune(s.c.alphabet.symbols[y])
+		}
+	}
+	if i != 0 {
+		return "", fmt.Errorf("%w, consider adding padding", ErrLengthNotMultiple)
+	}
+	return result.String(), nil
+}
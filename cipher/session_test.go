@@ -0,0 +1,78 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSession verify sessions produce the same results as the cipher
+func TestSession(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name, key, msg string
+		opts           []Option
+	}{
+		{name: "order 3", key: "FORTALEZA", msg: strings.Repeat("CONSUL", 50)},
+		{name: "order 2 affine", key: "IKEYBC", msg: strings.Repeat("CRIPTOGRAFIA", 50)},
+		{name: "order 3 affine", key: "FORTALEZAÑYZ", msg: strings.Repeat("CONSUL", 50)},
+		{name: "length padding", key: "FORTALEZA", msg: "ÑANDU", opts: []Option{WithPadding(LengthPadding{})}},
+		{name: "CBC mode", key: "IKEYBC", msg: "CRIPTOGRAFIA", opts: []Option{WithMode(CBC{IV: []int{4, 2}})}},
+		{name: "progressive mode", key: "FORTALEZA", msg: "CONSULTAR", opts: []Option{WithMode(Progressive{})}},
+		{name: "CTR mode", key: "IKEY", msg: "CRIPTOGRAFIA", opts: []Option{WithMode(CTR{Nonce: []int{0, 26}})}},
+		{name: "empty message", key: "IKEY"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(spanish, test.opts...)
+			key, err := cipher.ParseKey(test.key)
+			if err != nil {
+				t.Fatalf("ParseKey(%q) returned unexpected error; %v", test.key, err)
+			}
+			session, err := cipher.NewSession(key)
+			if err != nil {
+				t.Fatalf("NewSession(%q) returned unexpected error; %v", test.key, err)
+			}
+			wantCipherText, _ := cipher.Encrypt(test.msg, test.key)
+			cipherText, err := session.Encrypt(test.msg)
+			if err != nil {
+				t.Fatalf("Encrypt(%q) returned unexpected error; %v", test.msg, err)
+			}
+			if cipherText != wantCipherText {
+				t.Errorf("Encrypt(%q) = %q, want %q", test.msg, cipherText, wantCipherText)
+			}
+			plainText, err := session.Decrypt(cipherText)
+			if err != nil {
+				t.Fatalf("Decrypt(%q) returned unexpected error; %v", cipherText, err)
+			}
+			if plainText != test.msg {
+				t.Errorf("Decrypt(%q) = %q, want %q", cipherText, plainText, test.msg)
+			}
+		})
+	}
+}
+
+// TestSession_Error verify validations of keys and messages
+func TestSession_Error(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	singularKey, _ := NewKey([]int{1, 0, 0, 2}, 27)
+	if _, err := cipher.NewSession(singularKey); err == nil {
+		t.Errorf("NewSession(\n%s) returned nil error, want non-nil", singularKey)
+	}
+
+	key, _ := NewAffineKey([]int{3, 3, 2, 5}, []int{1, 2}, 26)
+	session, _ := cipher.NewSession(key)
+	for _, msg := range []string{"HELp", "HEL", "HELPÑ"} {
+		if _, err := session.Encrypt(msg); err == nil {
+			t.Errorf("Encrypt(%q) returned nil error, want non-nil", msg)
+		}
+		if _, err := session.Decrypt(msg); err == nil {
+			t.Errorf("Decrypt(%q) returned nil error, want non-nil", msg)
+		}
+	}
+
+	cbc, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"), WithMode(CBC{IV: []int{1}}))
+	session, _ = cbc.NewSession(key)
+	if _, err := session.Decrypt("HELP"); err == nil {
+		t.Errorf("Decrypt(%q) with invalid IV returned nil error, want non-nil", "HELP")
+	}
+}
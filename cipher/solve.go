@@ -0,0 +1,208 @@
+package cipher
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Solutions is the set of solutions of a linear system A·x = b (mod n). Every solution is written
+// in a unique way as Particular + c_1·Kernel[1] + ... + c_k·Kernel[k] (mod n) with
+// 0 <= c_i < Orders[i], so the system has a unique solution when Kernel is empty.
+type Solutions struct {
+	// Modulus is the modulo n of the system.
+	Modulus int
+	// Particular is a solution of the system.
+	Particular []int
+	// Kernel generates the solutions of the homogeneous system A·x = 0 (mod n).
+	Kernel [][]int
+	// Orders holds the additive order of each Kernel vector, i.e. the least c > 0 such that
+	// c·Kernel[i] = 0 (mod n).
+	Orders []int
+}
+
+// Count returns the number of solutions.
+func (s *Solutions) Count() *big.Int {
+	count := big.NewInt(1)
+	for _, order := range s.Orders {
+		count.Mul(count, big.NewInt(int64(order)))
+	}
+	return count
+}
+
+// All returns every solution, or an error if there are more than limit.
+func (s *Solutions) All(limit int) ([][]int, error) {
+	if count := s.Count(); !count.IsInt64() || count.Int64() > int64(limit) {
+		return nil, fmt.Errorf("system has %s solutions, more than %d", count, limit)
+	}
+	all := [][]int{s.Particular}
+	for k, v := range s.Kernel {
+		combined := make([][]int, 0, len(all)*s.Orders[k])
+		for _, x := range all {
+			for c := 0; c < s.Orders[k]; c++ {
+				y := make([]int, len(x))
+				for i := range x {
+					y[i] = SumMod(x[i], ProductMod(c, v[i], s.Modulus), s.Modulus)
+				}
+				combined = append(combined, y)
+			}
+		}
+		all = combined
+	}
+	return all, nil
+}
+
+// SolveMod returns every solution of the linear system A·x = b (mod n), even when A is singular
+// mod n. The system is solved modulo each prime power p^k of n, where A is diagonalized by
+// invertible row and column operations (any entry of least p-valuation divides the others, so it
+// can be used as pivot), and the results are combined through the CRT. Returns ErrNoSolution if
+// the system is inconsistent.
+func SolveMod(A *Matrix, b []int, n int) (*Solutions, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("got modulo < 2")
+	}
+	if A.order < 1 {
+		return nil, fmt.Errorf("cannot solve system of order %d < 1", A.order)
+	}
+	if len(b) != A.order {
+		return nil, fmt.Errorf("got invalid vector size %d, want %d", len(b), A.order)
+	}
+
+	factors := Factorize(n)
+	moduli := make([]int, len(factors))
+	partials := make([]*Solutions, len(factors))
+	for f, pp := range factors {
+		moduli[f] = pp.Value()
+		partial, err := solveModPrimePower(A, b, pp)
+		if err != nil {
+			return nil, err
+		}
+		partials[f] = partial
+	}
+
+	// lift returns the vector that is v modulo factor f and zero modulo the others.
+	residues := make([]int, len(factors))
+	lift := func(v []int, f int) []int {
+		x := make([]int, len(v))
+		for i := range v {
+			for g := range residues {
+				residues[g] = 0
+			}
+			residues[f] = v[i]
+			x[i], _ = CRT(residues, moduli) // Neglect error since prime powers are coprime
+		}
+		return x
+	}
+	s := &Solutions{Modulus: n, Particular: make([]int, A.order)}
+	for f, partial := range partials {
+		for i, x := range lift(partial.Particular, f) {
+			s.Particular[i] = SumMod(s.Particular[i], x, n)
+		}
+		for k, v := range partial.Kernel {
+			s.Kernel = append(s.Kernel, lift(v, f))
+			s.Orders = append(s.Orders, partial.Orders[k])
+		}
+	}
+	return s, nil
+}
+
+// solveModPrimePower returns the solutions of A·x = b (mod p^k). Row and column operations turn
+// the system into D·y = U·b with D = U·A·V diagonal, whose entries are powers of p, and x = V·y.
+func solveModPrimePower(A *Matrix, b []int, pp PrimePower) (*Solutions, error) {
+	q, order := pp.Value(), A.order
+	a := A.copyDataMod(q, 0)
+	c := make([]int, order)
+	for i, x := range b {
+		c[i] = Residue(x, q)
+	}
+	v := identity(order).data
+
+	diagonal := make([]int, order) // Exponent of p in each diagonal entry, k for zero entries
+	for t := range diagonal {
+		diagonal[t] = pp.Exp
+	}
+	for t := 0; t < order; t++ {
+		pi, pj, pv := -1, -1, pp.Exp
+		for i := t; i < order; i++ {
+			for j := t; j < order; j++ {
+				if a[i][j] == 0 {
+					continue
+				}
+				if e := valuation(a[i][j], pp.Prime); e < pv {
+					pi, pj, pv = i, j, e
+				}
+			}
+		}
+		if pi < 0 {
+			break // The remaining block is zero
+		}
+		a[t], a[pi] = a[pi], a[t]
+		c[t], c[pi] = c[pi], c[t]
+		swapCols(a, t, pj)
+		swapCols(v, t, pj)
+
+		// The pivot is p^e·u with u a unit, scaling the row by u^-1 leaves p^e
+		g := power(pp.Prime, pv)
+		inverse, _ := ModularInverse(a[t][t]/g, q) // Neglect error since u is coprime with p
+		for j := range a[t] {
+			a[t][j] = ProductMod(a[t][j], inverse, q)
+		}
+		c[t] = ProductMod(c[t], inverse, q)
+		for i := t + 1; i < order; i++ {
+			f := a[i][t] / g
+			subtractRowMod(a[i], a[t], f, q)
+			c[i] = SumMod(c[i], -ProductMod(f, c[t], q), q)
+		}
+		for j := t + 1; j < order; j++ {
+			f := a[t][j] / g
+			for i := range a {
+				a[i][j] = SumMod(a[i][j], -ProductMod(f, a[i][t], q), q)
+				v[i][j] = SumMod(v[i][j], -ProductMod(f, v[i][t], q), q)
+			}
+		}
+		diagonal[t] = pv
+	}
+
+	// p^e·y = c (mod p^k) has solutions y = c/p^e + s·p^(k-e) for s in [0, p^e) if p^e divides c
+	s := &Solutions{Modulus: q, Particular: make([]int, order)}
+	y := make([]int, order)
+	for t, e := range diagonal {
+		g := power(pp.Prime, e)
+		if c[t]%g != 0 {
+			return nil, fmt.Errorf("%w modulo %d", ErrNoSolution, q)
+		}
+		y[t] = c[t] / g
+		if e > 0 {
+			step := q / g
+			kernel := make([]int, order)
+			for i := range kernel {
+				kernel[i] = ProductMod(step, v[i][t], q)
+			}
+			s.Kernel = append(s.Kernel, kernel)
+			s.Orders = append(s.Orders, g)
+		}
+	}
+	vm := &Matrix{order: order, data: v}
+	s.Particular, _ = vm.VectorProductMod(q, y...) // Neglect error since size is exact
+	return s, nil
+}
+
+// valuation returns the exponent of the prime p in x. Assumes x != 0.
+func valuation(x, p int) int {
+	e := 0
+	for ; x%p == 0; x /= p {
+		e++
+	}
+	return e
+}
+
+// power returns p^e. Assumes the result fits in an int.
+func power(p, e int) int {
+	return PrimePower{Prime: p, Exp: e}.Value()
+}
+
+// swapCols swaps columns i and j of a.
+func swapCols(a [][]int, i, j int) {
+	for _, row := range a {
+		row[i], row[j] = row[j], row[i]
+	}
+}
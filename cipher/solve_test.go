@@ -0,0 +1,200 @@
+package cipher
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	cmp "github.com/google/go-cmp/cmp"
+)
+
+// TestSolveMod verify solutions of small systems
+func TestSolveMod(t *testing.T) {
+	tests := []struct {
+		name           string
+		a              *Matrix
+		b              []int
+		n              int
+		wantParticular []int
+		wantCount      int64
+	}{
+		{
+			name:           "invertible",
+			a:              &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}},
+			b:              []int{7, 8},
+			n:              26,
+			wantParticular: []int{7, 4},
+			wantCount:      1,
+		},
+		{
+			name:           "singular mod 2",
+			a:              &Matrix{order: 2, data: [][]int{{2, 0}, {0, 1}}},
+			b:              []int{4, 3},
+			n:              26,
+			wantParticular: []int{2, 3},
+			wantCount:      2,
+		},
+		{
+			name:           "zero matrix",
+			a:              &Matrix{order: 2, data: [][]int{{0, 0}, {0, 0}}},
+			b:              []int{0, 0},
+			n:              6,
+			wantParticular: []int{0, 0},
+			wantCount:      36,
+		},
+		{
+			name:           "order 1 prime power",
+			a:              &Matrix{order: 1, data: [][]int{{9}}},
+			b:              []int{18},
+			n:              27,
+			wantParticular: []int{2},
+			wantCount:      9,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := SolveMod(test.a, test.b, test.n)
+			if err != nil {
+				t.Fatalf("SolveMod(%v, %d) returned unexpected error; %v", test.b, test.n, err)
+			}
+			if diff := cmp.Diff(test.wantParticular, s.Particular); diff != "" {
+				t.Errorf("SolveMod(%v, %d) particular solution mismatch (-want +got):\n%s", test.b, test.n, diff)
+			}
+			if got := s.Count(); got.Cmp(big.NewInt(test.wantCount)) != 0 {
+				t.Errorf("SolveMod(%v, %d) has %s solutions, want %d", test.b, test.n, got, test.wantCount)
+			}
+		})
+	}
+}
+
+// TestSolveMod_BruteForce verify every solution is found by comparing against exhaustive search
+func TestSolveMod_BruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(23))
+	for _, n := range []int{2, 4, 6, 8, 9, 12, 26, 27} {
+		for order := 1; order <= 3; order++ {
+			for trial := 0; trial < 10; trial++ {
+				data := make([]int, order*order)
+				for i := range data {
+					// Favor multiples of the prime factors of n so singular systems are common
+					data[i] = rnd.Intn(n) * (1 + rnd.Intn(2)*Factorize(n)[0].Prime)
+				}
+				a, _ := NewMatrix(order, data)
+				b := make([]int, order)
+				for i := range b {
+					b[i] = rnd.Intn(n)
+				}
+				t.Run(fmt.Sprintf("%v·x=%v mod %d", a.data, b, n), func(t *testing.T) {
+					want := bruteForceSolve(a, b, n)
+					s, err := SolveMod(a, b, n)
+					if len(want) == 0 {
+						if !errors.Is(err, ErrNoSolution) {
+							t.Fatalf("SolveMod() = %v, want %v", err, ErrNoSolution)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatalf("SolveMod() returned unexpected error; %v", err)
+					}
+					got, err := s.All(len(want))
+					if err != nil {
+						t.Fatalf("All(%d) returned unexpected error; %v", len(want), err)
+					}
+					sortVectors(got)
+					if diff := cmp.Diff(want, got); diff != "" {
+						t.Errorf("SolveMod() solutions mismatch (-want +got):\n%s", diff)
+					}
+				})
+			}
+		}
+	}
+}
+
+// TestSolveMod_Error verify invalid systems are rejected
+func TestSolveMod_Error(t *testing.T) {
+	a := &Matrix{order: 2, data: [][]int{{3, 3}, {2, 5}}}
+	tests := []struct {
+		name    string
+		a       *Matrix
+		b       []int
+		n       int
+		wantErr error
+	}{
+		{name: "mod 1", a: a, b: []int{1, 2}, n: 1},
+		{name: "order 0", a: &Matrix{}, b: []int{}, n: 26},
+		{name: "invalid vector size", a: a, b: []int{1, 2, 3}, n: 26},
+		{
+			name:    "inconsistent",
+			a:       &Matrix{order: 2, data: [][]int{{2, 0}, {0, 1}}},
+			b:       []int{3, 1},
+			n:       26,
+			wantErr: ErrNoSolution,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := SolveMod(test.a, test.b, test.n)
+			if err == nil {
+				t.Fatalf("SolveMod(%v, %d) returned nil error, want non-nil", test.b, test.n)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("SolveMod(%v, %d) = %v, want %v", test.b, test.n, err, test.wantErr)
+			}
+		})
+	}
+}
+
+// TestSolutionsAll_Error verify solutions are not listed beyond the limit
+func TestSolutionsAll_Error(t *testing.T) {
+	s, _ := SolveMod(&Matrix{order: 2, data: [][]int{{0, 0}, {0, 0}}}, []int{0, 0}, 6)
+	if _, err := s.All(35); err == nil {
+		t.Errorf("All(35) of %s solutions returned nil error, want non-nil", s.Count())
+	}
+}
+
+// bruteForceSolve returns every solution of a·x = b (mod n) in lexicographic order.
+func bruteForceSolve(a *Matrix, b []int, n int) [][]int {
+	var solutions [][]int
+	x := make([]int, a.order)
+	for {
+		if isSolution(a, x, b, n) {
+			solutions = append(solutions, append([]int(nil), x...))
+		}
+		i := a.order - 1
+		for ; i >= 0 && x[i] == n-1; i-- {
+			x[i] = 0
+		}
+		if i < 0 {
+			return solutions
+		}
+		x[i]++
+	}
+}
+
+// isSolution returns whether a·x = b (mod n). Entries are small, so products don't overflow.
+func isSolution(a *Matrix, x, b []int, n int) bool {
+	for i, row := range a.data {
+		sum := 0
+		for j, y := range row {
+			sum += y * x[j]
+		}
+		if Residue(sum, n) != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortVectors sorts vectors in lexicographic order.
+func sortVectors(vectors [][]int) {
+	sort.Slice(vectors, func(i, j int) bool {
+		for k := range vectors[i] {
+			if vectors[i][k] != vectors[j][k] {
+				return vectors[i][k] < vectors[j][k]
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,257 @@
+package cipher
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// streamChunkSize is the number of bytes read at once from the underlying reader of a stream.
+const streamChunkSize = 4096
+
+// checkStreamOptions returns an error if the cipher's options can't be applied to streams.
+// RandomPadding prefixes the whole message with its padding length, which isn't known until the
+// stream ends, and unknown symbols can't be kept in place since blocks are written as soon as
+// they are complete.
+func (c *Cipher) checkStreamOptions() error {
+	if _, ok := c.padding.(RandomPadding); ok {
+		return fmt.Errorf("random padding prefixes the message and cannot be used on streams")
+	}
+	if c.normalizer != nil && c.normalizer.Unknown == KeepUnknown {
+		return fmt.Errorf("unknown symbols cannot be kept on streams")
+	}
+	return nil
+}
+
+// decodeSymbols appends to symbols every complete UTF-8 encoded rune in buf, verifying it
+// belongs to the alphabet. If the cipher has a normalizer, runes are normalized one by one (so
+// decomposed sequences are not composed). Returns the symbols and the number of bytes consumed
+// from buf. pos is the number of runes decoded before buf, and it is increased by the runes
+// consumed.
+func (c *Cipher) decodeSymbols(symbols []rune, buf []byte, pos *int) ([]rune, int, error) {
+	i := 0
+	for i < len(buf) && utf8.FullRune(buf[i:]) {
+		r, size := utf8.DecodeRune(buf[i:])
+		s, found := r, c.alphabet.Contains(r)
+		if c.normalizer != nil {
+			if c.strippedMark(r) {
+				i += size
+				*pos++
+				continue
+			}
+			s, found = c.normalizeSymbol(r)
+		}
+		switch {
+		case found:
+			symbols = append(symbols, s)
+		case c.normalizer == nil || c.normalizer.Unknown == RejectUnknown:
+			return symbols, i, fmt.Errorf("text does not belong to alphabet %q; %w", c.alphabet, &SymbolError{Symbol: r, Position: *pos})
+		}
+		i += size
+		*pos++
+	}
+	return symbols, i, nil
+}
+
+// encryptWriter encrypts the symbols written to it block by block.
+type encryptWriter struct {
+	c       *Cipher
+	order   int
+	op      BlockFunc
+	w       io.Writer
+	partial []byte // Trailing bytes of an incomplete UTF-8 encoded rune
+	pending []rune // Symbols of an incomplete block
+	decoded int    // Number of runes decoded so far
+	err     error
+}
+
+// NewEncryptWriter returns a writer that encrypts the text written to it using the given key
+// and writes the cipher text to w. Complete blocks are encrypted and written as soon as they
+// are available, so text of any size can be encrypted with constant memory. Close must be called
+// to pad (if the cipher has padding) and flush the last block; it doesn't close w.
+func (c *Cipher) NewEncryptWriter(w io.Writer, rawK string) (io.WriteCloser, error) {
+	key, err := c.ParseKey(rawK)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewEncryptWriterWithKey(w, key)
+}
+
+// NewEncryptWriterWithKey is like NewEncryptWriter but uses a pre-built key. Returns an error if
+// key is not invertible by cipher's modulo.
+func (c *Cipher) NewEncryptWriterWithKey(w io.Writer, key *Key) (io.WriteCloser, error) {
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	if err := c.checkStreamOptions(); err != nil {
+		return nil, err
+	}
+	encrypt, err := c.encrypter(key)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptWriter{c: c, order: key.matrix.order, op: encrypt, w: w}, nil
+}
+
+// Write implements io.Writer
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	prev := len(e.partial)
+	buf := append(e.partial, p...)
+	var n int
+	e.pending, n, e.err = e.c.decodeSymbols(e.pending, buf, &e.decoded)
+	e.partial = append([]byte(nil), buf[n:]...)
+	if e.err != nil {
+		if n < prev {
+			return 0, e.err
+		}
+		return n - prev, e.err
+	}
+	if e.err = e.flush(); e.err != nil {
+		return 0, e.err
+	}
+	return len(p), nil
+}
+
+// flush encrypts and writes all complete blocks in pending.
+func (e *encryptWriter) flush() error {
+	n := len(e.pending) - len(e.pending)%e.order
+	if n == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(e.w, e.c.performOperations(e.op, e.order, e.pending[:n])); err != nil {
+		return err
+	}
+	e.pending = append(e.pending[:0], e.pending[n:]...)
+	return nil
+}
+
+// Close implements io.Closer
+func (e *encryptWriter) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = fmt.Errorf("write to closed encrypt writer")
+	if len(e.partial) != 0 {
+		return fmt.Errorf("text ends with an incomplete UTF-8 sequence")
+	}
+	if e.c.padding != nil {
+		padded, err := e.c.padding.Pad(e.pending, e.order, &e.c.alphabet)
+		if err != nil {
+			return fmt.Errorf("failed to pad message; %w", err)
+		}
+		e.pending = padded
+	}
+	if len(e.pending)%e.order != 0 {
+		return fmt.Errorf("%w, consider adding padding", ErrLengthNotMultiple)
+	}
+	return e.flush()
+}
+
+// decryptReader decrypts the symbols read from an underlying reader block by block.
+type decryptReader struct {
+	c       *Cipher
+	order   int
+	op      BlockFunc
+	r       io.Reader
+	partial []byte // Trailing bytes of an incomplete UTF-8 encoded rune
+	pending []rune // Symbols of an incomplete block
+	held    []rune // Last decrypted block, held until EOF to remove its padding
+	out     []byte // Decrypted text not read yet
+	decoded int    // Number of runes decoded so far
+	err     error
+}
+
+// NewDecryptReader returns a reader that decrypts the cipher text read from r using the given
+// key. Blocks are decrypted as they are read, so text of any size can be decrypted with constant
+// memory. If the cipher has padding, the last block is held until r is exhausted and its padding
+// removed.
+func (c *Cipher) NewDecryptReader(r io.Reader, rawK string) (io.Reader, error) {
+	key, err := c.ParseKey(rawK)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewDecryptReaderWithKey(r, key)
+}
+
+// NewDecryptReaderWithKey is like NewDecryptReader but uses a pre-built key. Returns an error if
+// key is not invertible by cipher's modulo.
+func (c *Cipher) NewDecryptReaderWithKey(r io.Reader, key *Key) (io.Reader, error) {
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	if err := c.checkStreamOptions(); err != nil {
+		return nil, err
+	}
+	decrypt, err := c.decrypter(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{c: c, order: key.matrix.order, op: decrypt, r: r}, nil
+}
+
+// Read implements io.Reader
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.fill()
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// fill reads the next chunk from the underlying reader and decrypts its complete blocks. At the
+// end of the stream, the last block is unpadded and any incomplete data is reported as error.
+func (d *decryptReader) fill() {
+	buf := make([]byte, streamChunkSize)
+	n, err := d.r.Read(buf)
+	buf = append(d.partial, buf[:n]...)
+	var consumed int
+	d.pending, consumed, d.err = d.c.decodeSymbols(d.pending, buf, &d.decoded)
+	d.partial = append([]byte(nil), buf[consumed:]...)
+	if d.err != nil {
+		return
+	}
+
+	if complete := len(d.pending) - len(d.pending)%d.order; complete > 0 {
+		plainText := []rune(d.c.performOperations(d.op, d.order, d.pending[:complete]))
+		d.pending = append(d.pending[:0], d.pending[complete:]...)
+		if d.c.padding != nil {
+			plainText = append(d.held, plainText...)
+			d.held = append([]rune(nil), plainText[len(plainText)-d.order:]...)
+			plainText = plainText[:len(plainText)-d.order]
+		}
+		d.out = append(d.out, string(plainText)...)
+	}
+
+	switch {
+	case err == io.EOF:
+		d.err = d.finish()
+	case err != nil:
+		d.err = err
+	}
+}
+
+// finish validates the end of the stream and appends the unpadded last block to the output.
+// Returns io.EOF if the stream ended correctly.
+func (d *decryptReader) finish() error {
+	if len(d.partial) != 0 {
+		return fmt.Errorf("cipher text ends with an incomplete UTF-8 sequence")
+	}
+	if len(d.pending) != 0 {
+		return fmt.Errorf("invalid cipher text; %w", ErrLengthNotMultiple)
+	}
+	if d.c.padding != nil {
+		unpadded, err := d.c.padding.Unpad(d.held, d.order, &d.c.alphabet)
+		if err != nil {
+			return fmt.Errorf("failed to remove padding from %q; %w", string(d.held), err)
+		}
+		d.out = append(d.out, string(unpadded)...)
+	}
+	return io.EOF
+}
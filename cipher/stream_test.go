@@ -0,0 +1,280 @@
+package cipher
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// errWriter fails every write
+type errWriter struct{}
+
+// Write implements io.Writer
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// errReader returns its content followed by an error different from io.EOF
+type errReader struct {
+	r io.Reader
+}
+
+// Read implements io.Reader
+func (e errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("read failed")
+	}
+	return n, err
+}
+
+// TestStream verify streaming encryption and decryption match whole-string operations
+func TestStream(t *testing.T) {
+	spanish := "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"
+	tests := []struct {
+		name, alphabet, key, msg string
+		padding                  Padding
+		mode                     Mode
+		normalizer               *Normalizer
+		wantPlainText            string // Defaults to msg
+	}{
+		{
+			name:     "spanish order 3",
+			alphabet: spanish,
+			key:      "FORTALEZA",
+			msg:      strings.Repeat("UUNAMFCIENCIASÑÑÑX", 1000),
+		},
+		{
+			name:     "spanish order 3 filler padding",
+			alphabet: spanish,
+			key:      "FORTALEZA",
+			msg:      strings.Repeat("ÑANDU", 1001),
+			padding:  FillerPadding{Filler: 'X'},
+		},
+		{
+			name:     "spanish order 2 length padding",
+			alphabet: spanish,
+			key:      "IKEY",
+			msg:      strings.Repeat("CRIPTOGRAFIA", 500),
+			padding:  LengthPadding{},
+		},
+		{
+			name:     "length padding empty message",
+			alphabet: spanish,
+			key:      "IKEY",
+			padding:  LengthPadding{},
+		},
+		{
+			name:     "spanish order 3 CBC mode length padding",
+			alphabet: spanish,
+			key:      "FORTALEZA",
+			msg:      strings.Repeat("ÑANDU", 1001),
+			padding:  LengthPadding{},
+			mode:     CBC{IV: []int{1, 2, 3}},
+		},
+		{
+			name:     "spanish order 2 CTR mode",
+			alphabet: spanish,
+			key:      "IKEY",
+			msg:      strings.Repeat("CRIPTOGRAFIA", 500),
+			mode:     CTR{Nonce: []int{26, 20}},
+		},
+		{
+			name:     "spanish order 2 affine key CBC mode",
+			alphabet: spanish,
+			key:      "IKEYBC",
+			msg:      strings.Repeat("CRIPTOGRAFIA", 500),
+			mode:     CBC{IV: []int{3, 7}},
+		},
+		{
+			name:     "spanish order 2 progressive mode",
+			alphabet: spanish,
+			key:      "IKEY",
+			msg:      strings.Repeat("CRIPTOGRAFIA", 500),
+			mode:     Progressive{},
+		},
+		{
+			name:          "spanish order 2 normalizer",
+			alphabet:      spanish,
+			key:           "IKEY",
+			msg:           strings.Repeat("Árbol ñandú.\u0301 ", 100),
+			normalizer:    &Normalizer{FoldCase: true, StripDiacritics: true, Unknown: DropUnknown},
+			wantPlainText: strings.Repeat("ARBOLÑANDU", 100),
+		},
+		{
+			name:     "binary",
+			alphabet: "01",
+			key:      "1011",
+			msg:      "0110101100101101",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.padding != nil {
+				opts = append(opts, WithPadding(test.padding))
+			}
+			if test.mode != nil {
+				opts = append(opts, WithMode(test.mode))
+			}
+			if test.normalizer != nil {
+				opts = append(opts, WithNormalizer(*test.normalizer))
+			}
+			if test.wantPlainText == "" {
+				test.wantPlainText = test.msg
+			}
+			cipher, _ := NewCipher(NewAlphabet(test.alphabet), opts...)
+			wantCipherText, err := cipher.Encrypt(test.msg, test.key)
+			if err != nil {
+				t.Fatalf("Encrypt(key:%q) returned unexpected error; %v", test.key, err)
+			}
+
+			// Write one byte at a time so runes and blocks are split across writes
+			var cipherText bytes.Buffer
+			w, err := cipher.NewEncryptWriter(&cipherText, test.key)
+			if err != nil {
+				t.Fatalf("NewEncryptWriter(key:%q) returned unexpected error; %v", test.key, err)
+			}
+			for _, b := range []byte(test.msg) {
+				if _, err := w.Write([]byte{b}); err != nil {
+					t.Fatalf("Write() returned unexpected error; %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() returned unexpected error; %v", err)
+			}
+			if cipherText.String() != wantCipherText {
+				t.Errorf("streamed cipher text differs from Encrypt(key:%q)", test.key)
+			}
+
+			r, err := cipher.NewDecryptReader(iotest.HalfReader(&cipherText), test.key)
+			if err != nil {
+				t.Fatalf("NewDecryptReader(key:%q) returned unexpected error; %v", test.key, err)
+			}
+			plainText, err := ioutil.ReadAll(iotest.OneByteReader(r))
+			if err != nil {
+				t.Fatalf("ReadAll() returned unexpected error; %v", err)
+			}
+			if string(plainText) != test.wantPlainText {
+				t.Errorf("streamed plain text differs from original message")
+			}
+		})
+	}
+}
+
+// TestEncryptWriter_Error verify validations on encryption streams
+func TestEncryptWriter_Error(t *testing.T) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	tests := []struct {
+		name, key, msg string
+		w              io.Writer
+		opts           []Option
+		failsOnWrite   bool
+	}{
+		{name: "invalid key", key: "AAAA", msg: "AB"},
+		{name: "random padding", key: "IKEY", opts: []Option{WithPadding(RandomPadding{})}},
+		{name: "invalid mode", key: "IKEY", opts: []Option{WithMode(CBC{})}},
+		{name: "keep unknown symbols", key: "IKEY", opts: []Option{WithNormalizer(Normalizer{Unknown: KeepUnknown})}},
+		{name: "symbol rejected by normalizer", key: "IKEY", msg: "AAAb", opts: []Option{WithNormalizer(Normalizer{})}, failsOnWrite: true},
+		{name: "symbol not in alphabet", key: "IKEY", msg: "AAAb", failsOnWrite: true},
+		{name: "underlying writer error", key: "IKEY", msg: "AAAA", w: errWriter{}, failsOnWrite: true},
+		{name: "incomplete block", key: "IKEY", msg: "AAA"},
+		{name: "incomplete rune", key: "IKEY", msg: "AAAA\xc3"},
+		{name: "invalid padding", key: "IKEY", msg: "AAA", opts: []Option{WithPadding(FillerPadding{Filler: 'x'})}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.w == nil {
+				test.w = ioutil.Discard
+			}
+			cipher, _ := NewCipher(alphabet, test.opts...)
+			w, err := cipher.NewEncryptWriter(test.w, test.key)
+			if err != nil {
+				return
+			}
+			_, err = w.Write([]byte(test.msg))
+			if test.failsOnWrite {
+				if err == nil {
+					t.Fatalf("Write(%q) returned nil error, want non-nil", test.msg)
+				}
+				if err := w.Close(); err == nil {
+					t.Errorf("Close() after failed Write returned nil error, want non-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write(%q) returned unexpected error; %v", test.msg, err)
+			}
+			if err := w.Close(); err == nil {
+				t.Errorf("Close() returned nil error, want non-nil")
+			}
+			if _, err := w.Write([]byte("A")); err == nil {
+				t.Errorf("Write() after Close returned nil error, want non-nil")
+			}
+		})
+	}
+}
+
+// TestEncryptWriter_PartialWrite verify the number of bytes written before an invalid symbol
+func TestEncryptWriter_PartialWrite(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"))
+	w, _ := cipher.NewEncryptWriter(ioutil.Discard, "IKEY")
+	w.Write([]byte("\xc3")) // First byte of Ñ
+	if n, err := w.Write([]byte("\x91Ab")); n != 2 || err == nil {
+		t.Errorf("Write() = %d, %v; want 2, non-nil error", n, err)
+	}
+	w, _ = cipher.NewEncryptWriter(ioutil.Discard, "IKEY")
+	w.Write([]byte("\xc3")) // First byte of Ñ
+	if n, err := w.Write([]byte("\xb1")); n != 0 || err == nil {
+		t.Errorf("Write() = %d, %v; want 0, non-nil error", n, err)
+	}
+}
+
+// TestDecryptReader_Error verify validations on decryption streams
+func TestDecryptReader_Error(t *testing.T) {
+	alphabet := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	plain, _ := NewCipher(alphabet)
+	badPadding, _ := plain.Encrypt("AZ", "IKEY") // Z means a padding of 27 symbols
+	tests := []struct {
+		name, key string
+		r         io.Reader
+		opts      []Option
+	}{
+		{name: "invalid key", key: "AAAA", r: strings.NewReader("AB")},
+		{name: "random padding", key: "IKEY", r: strings.NewReader("AB"), opts: []Option{WithPadding(RandomPadding{})}},
+		{name: "invalid mode", key: "IKEY", r: strings.NewReader("AB"), opts: []Option{WithMode(CTR{})}},
+		{name: "symbol not in alphabet", key: "IKEY", r: strings.NewReader("AAAb")},
+		{name: "underlying reader error", key: "IKEY", r: errReader{strings.NewReader("AAAA")}},
+		{name: "incomplete block", key: "IKEY", r: strings.NewReader("AAA")},
+		{name: "incomplete rune", key: "IKEY", r: strings.NewReader("AAAA\xc3")},
+		{name: "invalid padding", key: "IKEY", r: strings.NewReader(badPadding), opts: []Option{WithPadding(LengthPadding{})}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(alphabet, test.opts...)
+			r, err := cipher.NewDecryptReader(test.r, test.key)
+			if err != nil {
+				return
+			}
+			if _, err := ioutil.ReadAll(r); err == nil {
+				t.Errorf("ReadAll() returned nil error, want non-nil")
+			}
+		})
+	}
+}
+
+// TestStream_SingularKey verify streams reject pre-built keys that are not invertible
+func TestStream_SingularKey(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	key, _ := NewKey([]int{1, 0, 0, 2}, 27)
+	if _, err := cipher.NewEncryptWriterWithKey(ioutil.Discard, key); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("NewEncryptWriterWithKey() returned error %v, want %v", err, ErrNotInvertible)
+	}
+	if _, err := cipher.NewDecryptReaderWithKey(strings.NewReader("AB"), key); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("NewDecryptReaderWithKey() returned error %v, want %v", err, ErrNotInvertible)
+	}
+}
@@ -0,0 +1,170 @@
+package cipher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trace is a step-by-step record of an encryption or decryption, meant for teaching how the Hill
+// cipher works. Use its String method to render it as readable text.
+type Trace struct {
+	// Decryption is whether the trace records a decryption instead of an encryption.
+	Decryption bool
+	// Modulus is the size of the cipher's alphabet.
+	Modulus int
+	// Key is the key matrix and Shift its translation vector (nil if the key isn't affine).
+	Key   *Matrix
+	Shift []int
+	// Determinant is det(K) mod n and DeterminantInverse its modular inverse. Only set for
+	// decryption, as well as Adjoint, adj(K) mod n, and Inverse, the inverse key K^-1 mod n.
+	Determinant, DeterminantInverse int
+	Adjoint, Inverse                *Matrix
+	// Blocks are the transformed blocks of the message, after normalization and padding.
+	Blocks []TraceBlock
+	// Result is the encrypted or decrypted message.
+	Result string
+}
+
+// TraceBlock is the transformation of a single block of a message.
+type TraceBlock struct {
+	// Symbols are the symbols of the block and Vector their numeric values.
+	Symbols []rune
+	Vector  []int
+	// Product is the key product before reduction, K·P + b when encrypting and K^-1·(C - b) when
+	// decrypting, where C - b is reduced mod n. It may overflow for very large moduli.
+	Product []int
+	// Residues are the entries of Product reduced mod n, the numeric values of Output.
+	Residues []int
+	Output   []rune
+}
+
+// TraceEncrypt encrypts plain text like EncryptWithKey and returns the trace of every step.
+// Returns an error on the same conditions as EncryptWithKey or if the cipher's mode of operation
+// is not ECB, since other modes chain blocks.
+func (c *Cipher) TraceEncrypt(rawM string, key *Key) (*Trace, error) {
+	t, err := c.newTrace(key, false)
+	if err != nil {
+		return nil, err
+	}
+	msg, kept, err := c.verifyText(rawM, key.matrix.order, true)
+	if err != nil {
+		return nil, err
+	}
+	t.Result = restore(c.performOperations(t.blockFunc(&c.alphabet), key.matrix.order, msg), kept)
+	return t, nil
+}
+
+// TraceDecrypt decrypts cipher text like DecryptWithKey and returns the trace of every step,
+// including the computation of the inverse key. Returns an error on the same conditions as
+// DecryptWithKey or if the cipher's mode of operation is not ECB, since other modes chain blocks.
+func (c *Cipher) TraceDecrypt(rawM string, key *Key) (*Trace, error) {
+	t, err := c.newTrace(key, true)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, kept, err := c.verifyText(rawM, key.matrix.order, false)
+	if err != nil {
+		return nil, err
+	}
+	plainText := c.performOperations(t.blockFunc(&c.alphabet), key.matrix.order, cipherText)
+	if t.Result, err = c.unpad(plainText, key.matrix.order, kept); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// newTrace returns an empty trace of the cipher for key, computing the inverse key if decryption
+// is set. Returns an error if key is not invertible by cipher's modulo or if the cipher's mode of
+// operation is not ECB.
+func (c *Cipher) newTrace(key *Key, decryption bool) (*Trace, error) {
+	if err := c.verifyKey(key); err != nil {
+		return nil, err
+	}
+	if _, ecb := c.blockMode().(ECB); !ecb {
+		return nil, fmt.Errorf("tracing is only supported in ECB mode, got %T", c.blockMode())
+	}
+	t := &Trace{Decryption: decryption, Modulus: c.mod, Key: key.Matrix(), Shift: key.Shift()}
+	if !decryption {
+		return t, nil
+	}
+	// Neglect errors since key was verified
+	t.Determinant, _ = key.matrix.DeterminantMod(c.mod)
+	t.DeterminantInverse, _ = ModularInverse(t.Determinant, c.mod)
+	t.Adjoint, _ = key.matrix.AdjointMod(c.mod)
+	t.Inverse, _ = key.matrix.InverseMod(c.mod)
+	return t, nil
+}
+
+// blockFunc returns the function that transforms a block and appends its record to the trace.
+func (t *Trace) blockFunc(alphabet *Alphabet) BlockFunc {
+	return func(vector []int) []int {
+		m, input := t.Key, vector
+		if t.Decryption {
+			m, input = t.Inverse, make([]int, len(vector))
+			copy(input, vector)
+			for i, b := range t.Shift {
+				input[i] = Residue(input[i]-b, t.Modulus)
+			}
+		}
+		block := TraceBlock{
+			Symbols:  make([]rune, len(vector)),
+			Vector:   vector,
+			Product:  make([]int, len(vector)),
+			Residues: make([]int, len(vector)),
+			Output:   make([]rune, len(vector)),
+		}
+		for i, row := range m.data {
+			for j, x := range row {
+				block.Product[i] += x * input[j]
+			}
+			if !t.Decryption && t.Shift != nil {
+				block.Product[i] += t.Shift[i]
+			}
+			block.Residues[i] = Residue(block.Product[i], t.Modulus)
+			// Neglect errors since vector and residues are in Zn
+			block.Symbols[i], _ = alphabet.Itos(vector[i])
+			block.Output[i], _ = alphabet.Itos(block.Residues[i])
+		}
+		t.Blocks = append(t.Blocks, block)
+		return block.Residues
+	}
+}
+
+// labels returns the name of the traced operation, the names of its input and output blocks and
+// the formula of the key product.
+func (t Trace) labels() (operation, in, out, product string) {
+	switch {
+	case t.Decryption && t.Shift != nil:
+		return "Decryption", "C", "P", "K^-1·(C - b)"
+	case t.Decryption:
+		return "Decryption", "C", "P", "K^-1·C"
+	case t.Shift != nil:
+		return "Encryption", "P", "C", "K·P + b"
+	}
+	return "Encryption", "P", "C", "K·P"
+}
+
+// String makes Trace implement Stringer, explaining every step of the encryption or decryption.
+func (t Trace) String() string {
+	var b strings.Builder
+	operation, in, out, product := t.labels()
+	fmt.Fprintf(&b, "%s modulo %d with key K =\n%s", operation, t.Modulus, t.Key)
+	if t.Shift != nil {
+		fmt.Fprintf(&b, "and translation vector b = %v\n", t.Shift)
+	}
+	if t.Decryption {
+		fmt.Fprintf(&b, "\ndet(K) mod %d = %d\n", t.Modulus, t.Determinant)
+		fmt.Fprintf(&b, "det(K)^-1 mod %d = %d\n", t.Modulus, t.DeterminantInverse)
+		fmt.Fprintf(&b, "adj(K) mod %d =\n%s", t.Modulus, t.Adjoint)
+		fmt.Fprintf(&b, "K^-1 = det(K)^-1 · adj(K) mod %d =\n%s", t.Modulus, t.Inverse)
+	}
+	for i, block := range t.Blocks {
+		fmt.Fprintf(&b, "\nBlock %d: %s = %q\n", i+1, in, string(block.Symbols))
+		fmt.Fprintf(&b, "\t%s = %v\n", in, block.Vector)
+		fmt.Fprintf(&b, "\t%s = %v\n", product, block.Product)
+		fmt.Fprintf(&b, "\t%s mod %d = %v\n", product, t.Modulus, block.Residues)
+		fmt.Fprintf(&b, "\t%s = %q\n", out, string(block.Output))
+	}
+	fmt.Fprintf(&b, "\nResult: %q\n", t.Result)
+	return b.String()
+}
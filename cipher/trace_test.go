@@ -0,0 +1,198 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestTrace verify every step of encryption and decryption is traced
+func TestTrace(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	key, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	affineKey, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	inverse := &Matrix{order: 2, data: [][]int{{1, 5}, {2, 23}}}
+	adjoint := &Matrix{order: 2, data: [][]int{{25, 17}, {23, 8}}}
+	tests := []struct {
+		name      string
+		key       *Key
+		opts      []Option
+		msg       string
+		decrypt   bool
+		wantTrace *Trace
+	}{
+		{
+			name: "encryption",
+			key:  key,
+			msg:  "HOLA",
+			wantTrace: &Trace{
+				Modulus: 27,
+				Key:     &key.matrix,
+				Blocks: []TraceBlock{
+					{Symbols: []rune("HO"), Vector: []int{7, 15}, Product: []int{206, 403}, Residues: []int{17, 25}, Output: []rune("QY")},
+					{Symbols: []rune("LA"), Vector: []int{11, 0}, Product: []int{88, 44}, Residues: []int{7, 17}, Output: []rune("HQ")},
+				},
+				Result: "QYHQ",
+			},
+		},
+		{
+			name:    "decryption",
+			key:     key,
+			msg:     "QYHQ",
+			decrypt: true,
+			wantTrace: &Trace{
+				Decryption:         true,
+				Modulus:            27,
+				Key:                &key.matrix,
+				Determinant:        25,
+				DeterminantInverse: 13,
+				Adjoint:            adjoint,
+				Inverse:            inverse,
+				Blocks: []TraceBlock{
+					{Symbols: []rune("QY"), Vector: []int{17, 25}, Product: []int{142, 609}, Residues: []int{7, 15}, Output: []rune("HO")},
+					{Symbols: []rune("HQ"), Vector: []int{7, 17}, Product: []int{92, 405}, Residues: []int{11, 0}, Output: []rune("LA")},
+				},
+				Result: "HOLA",
+			},
+		},
+		{
+			name: "affine encryption with padding",
+			key:  affineKey,
+			opts: []Option{WithPadding(FillerPadding{Filler: 'X'})},
+			msg:  "SOL",
+			wantTrace: &Trace{
+				Modulus: 27,
+				Key:     &affineKey.matrix,
+				Shift:   []int{1, 2},
+				Blocks: []TraceBlock{
+					{Symbols: []rune("SO"), Vector: []int{19, 15}, Product: []int{303, 453}, Residues: []int{6, 21}, Output: []rune("GU")},
+					{Symbols: []rune("LX"), Vector: []int{11, 24}, Product: []int{329, 646}, Residues: []int{5, 25}, Output: []rune("FY")},
+				},
+				Result: "GUFY",
+			},
+		},
+		{
+			name:    "affine decryption with padding",
+			key:     affineKey,
+			opts:    []Option{WithPadding(FillerPadding{Filler: 'X'})},
+			msg:     "GUFY",
+			decrypt: true,
+			wantTrace: &Trace{
+				Decryption:         true,
+				Modulus:            27,
+				Key:                &affineKey.matrix,
+				Shift:              []int{1, 2},
+				Determinant:        25,
+				DeterminantInverse: 13,
+				Adjoint:            adjoint,
+				Inverse:            inverse,
+				Blocks: []TraceBlock{
+					{Symbols: []rune("GU"), Vector: []int{6, 21}, Product: []int{100, 447}, Residues: []int{19, 15}, Output: []rune("SO")},
+					{Symbols: []rune("FY"), Vector: []int{5, 25}, Product: []int{119, 537}, Residues: []int{11, 24}, Output: []rune("LX")},
+				},
+				Result: "SOL",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cipher, _ := NewCipher(spanish, test.opts...)
+			trace, op := cipher.TraceEncrypt, "TraceEncrypt"
+			if test.decrypt {
+				trace, op = cipher.TraceDecrypt, "TraceDecrypt"
+			}
+			got, err := trace(test.msg, test.key)
+			if err != nil {
+				t.Fatalf("%s(%q) returned unexpected error; %v", op, test.msg, err)
+			}
+			if diff := cmp.Diff(test.wantTrace, got, cmp.AllowUnexported(Matrix{})); diff != "" {
+				t.Errorf("%s(%q) diff want -> got\n%s", op, test.msg, diff)
+			}
+		})
+	}
+}
+
+// TestTrace_KeyCopy verify changing a trace doesn't change the traced key
+func TestTrace_KeyCopy(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"))
+	key, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	trace, err := cipher.TraceEncrypt("HOLA", key)
+	if err != nil {
+		t.Fatalf("TraceEncrypt(%q) returned unexpected error; %v", "HOLA", err)
+	}
+	trace.Key.data[0][0] = 0
+	trace.Shift[0] = 0
+	if key.matrix.data[0][0] != 8 || key.shift[0] != 1 {
+		t.Errorf("changing the trace changed the key to\n%s", key)
+	}
+}
+
+// TestTrace_Error verify tracing fails on the same conditions as encryption and decryption
+func TestTrace_Error(t *testing.T) {
+	spanish := NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+	key, _ := NewKey([]int{8, 10, 4, 25}, 27)
+	singularKey := &Key{matrix: Matrix{order: 2, data: [][]int{{1, 0}, {0, 3}}}}
+	cipher, _ := NewCipher(spanish)
+	cbc, _ := NewCipher(spanish, WithMode(CBC{IV: []int{1, 2}}))
+	padded, _ := NewCipher(spanish, WithPadding(LengthPadding{}))
+	tests := []struct {
+		name   string
+		cipher *Cipher
+		key    *Key
+		msg    string
+	}{
+		{name: "key not invertible", cipher: cipher, key: singularKey, msg: "HOLA"},
+		{name: "chained mode", cipher: cbc, key: key, msg: "HOLA"},
+		{name: "message not multiple of order", cipher: cipher, key: key, msg: "SOL"},
+		{name: "message does not belong", cipher: cipher, key: key, msg: "hola"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.cipher.TraceEncrypt(test.msg, test.key); err == nil {
+				t.Errorf("TraceEncrypt(%q) returned nil error, want non-nil", test.msg)
+			}
+			if _, err := test.cipher.TraceDecrypt(test.msg, test.key); err == nil {
+				t.Errorf("TraceDecrypt(%q) returned nil error, want non-nil", test.msg)
+			}
+		})
+	}
+	if _, err := padded.TraceDecrypt("QYAN", key); err == nil {
+		t.Errorf("TraceDecrypt(%q) with malformed padding returned nil error, want non-nil", "QYAN")
+	}
+}
+
+// TestTraceString verify traces are rendered as readable text
+func TestTraceString(t *testing.T) {
+	cipher, _ := NewCipher(NewAlphabet("ABCDEFGHIJKLMNÑOPQRSTUVWXYZ"))
+	key, _ := NewAffineKey([]int{8, 10, 4, 25}, []int{1, 2}, 27)
+	trace, _ := cipher.TraceDecrypt("GU", key)
+	want := "Decryption modulo 27 with key K =\n" +
+		"|\t8\t|\t10\t|\n" +
+		"|\t4\t|\t25\t|\n" +
+		"and translation vector b = [1 2]\n" +
+		"\n" +
+		"det(K) mod 27 = 25\n" +
+		"det(K)^-1 mod 27 = 13\n" +
+		"adj(K) mod 27 =\n" +
+		"|\t25\t|\t17\t|\n" +
+		"|\t23\t|\t8\t|\n" +
+		"K^-1 = det(K)^-1 · adj(K) mod 27 =\n" +
+		"|\t1\t|\t5\t|\n" +
+		"|\t2\t|\t23\t|\n" +
+		"\n" +
+		"Block 1: C = \"GU\"\n" +
+		"\tC = [6 21]\n" +
+		"\tK^-1·(C - b) = [100 447]\n" +
+		"\tK^-1·(C - b) mod 27 = [19 15]\n" +
+		"\tP = \"SO\"\n" +
+		"\n" +
+		"Result: \"SO\"\n"
+	if got := trace.String(); got != want {
+		t.Errorf("String() = \n%s, want \n%s", got, want)
+	}
+	trace, _ = cipher.TraceEncrypt("SO", key)
+	if got := trace.String(); !strings.Contains(got, "\tK·P + b = [303 453]\n") {
+		t.Errorf("String() = \n%s, want it to contain the product K·P + b", got)
+	}
+}
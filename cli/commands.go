@@ -0,0 +1,571 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hcipher "github.com/pablotrinidad/hillcipher/cipher"
+	"github.com/pablotrinidad/hillcipher/cipher/attack"
+)
+
+// newFlagSet returns the flag set of the named command, whose help text shows the command
+// synopsis and description followed by the flags.
+func newFlagSet(name, synopsis, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: hillcipher %s %s\n\n%s\n\nFlags:\n", name, synopsis, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the command arguments. Returns flag.ErrHelp if help was requested, or a
+// usage error on invalid flags or positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return usageError("") // Already reported by the flag package
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Sprintf("got unexpected arguments %q", fs.Args()))
+	}
+	return nil
+}
+
+// ioFlags are the flags that define where a command reads its input and writes its output.
+type ioFlags struct {
+	text, in, out string
+}
+
+// register defines the input and output flags in fs, describing the input as what.
+func (f *ioFlags) register(fs *flag.FlagSet, what string) {
+	fs.StringVar(&f.text, "t", "", "the "+what+", instead of reading it from -in or the standard input")
+	fs.StringVar(&f.in, "in", "", "the file to read the "+what+" from, instead of the standard input")
+	fs.StringVar(&f.out, "out", "", "the file to write the result to, instead of the standard output")
+}
+
+// input returns a reader of the input text given by -t, read from -in or from the standard input.
+// A single trailing line break is removed from files and the standard input. It must be closed.
+func (f *ioFlags) input() (io.ReadCloser, error) {
+	if f.text != "" && f.in != "" {
+		return nil, usageError("only one of -t or -in arguments can be used")
+	}
+	if f.text != "" {
+		return ioutil.NopCloser(strings.NewReader(f.text)), nil
+	}
+	if f.in == "" {
+		return &lineTrimmer{ReadCloser: ioutil.NopCloser(os.Stdin)}, nil
+	}
+	file, err := os.Open(f.in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input; %v", err)
+	}
+	return &lineTrimmer{ReadCloser: file}, nil
+}
+
+// read returns the whole input text, as given by input.
+func (f *ioFlags) read() (string, error) {
+	r, err := f.input()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input; %v", err)
+	}
+	return string(data), nil
+}
+
+// output returns the writer of -out or the standard output, and the function that finishes it
+// given the error of whatever was written. The file given by -out is written to a temporary file
+// in the same directory, which replaces it only if there was no error and is removed otherwise.
+// The finish function returns the given error or the one finishing the output.
+func (f *ioFlags) output() (io.Writer, func(error) error, error) {
+	if f.out == "" {
+		return os.Stdout, func(err error) error { return err }, nil
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.out), "."+filepath.Base(f.out)+".*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to write output; %v", err)
+	}
+	finish := func(err error) error {
+		if closeErr := tmp.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write output; %v", closeErr)
+		}
+		if err == nil {
+			if err = os.Rename(tmp.Name(), f.out); err == nil {
+				return nil
+			}
+			err = fmt.Errorf("failed to write output; %v", err)
+		}
+		os.Remove(tmp.Name()) // Neglect error since the output already failed
+		return err
+	}
+	return tmp, finish, nil
+}
+
+// write writes s to -out or to the standard output.
+func (f *ioFlags) write(s string) error {
+	if f.out == "" {
+		_, err := fmt.Fprint(os.Stdout, s)
+		return err
+	}
+	if err := ioutil.WriteFile(f.out, []byte(s), 0600); err != nil {
+		return fmt.Errorf("failed to write output; %v", err)
+	}
+	return nil
+}
+
+// lineTrimmer reads from the underlying reader without its single trailing line break, either
+// "\n", "\r\n" or "\r". Line break bytes are held until it is known whether more text follows.
+type lineTrimmer struct {
+	io.ReadCloser
+	buf, out, held []byte
+	err            error
+}
+
+// Read implements io.Reader
+func (l *lineTrimmer) Read(p []byte) (int, error) {
+	for len(l.out) == 0 {
+		if l.err != nil {
+			return 0, l.err
+		}
+		if l.buf == nil {
+			l.buf = make([]byte, 4096)
+		}
+		n := copy(l.buf, l.held)
+		m, err := l.ReadCloser.Read(l.buf[n:])
+		text := l.buf[:n+m]
+		hold := 0
+		switch {
+		case bytes.HasSuffix(text, []byte("\r\n")):
+			hold = 2
+		case bytes.HasSuffix(text, []byte("\n")), bytes.HasSuffix(text, []byte("\r")):
+			hold = 1
+		}
+		// Once the reader is exhausted the held bytes are the trailing line break, so they are dropped
+		l.out, l.held, l.err = text[:len(text)-hold], text[len(text)-hold:], err
+	}
+	n := copy(p, l.out)
+	l.out = l.out[n:]
+	return n, nil
+}
+
+// cipherFlags are the flags that define the cipher of a command and, optionally, its key.
+type cipherFlags struct {
+	alphabet, padding              string
+	key, keyMatrix, keyFile, shift string
+}
+
+// register defines the cipher flags in fs, including the key flags if withKey is set.
+func (f *cipherFlags) register(fs *flag.FlagSet, withKey bool) {
+	fs.StringVar(&f.alphabet, "a", "", "the alphabet that will be used in the cipher, either a registered name like 'spanish' or its symbols with optional ranges like 'A-Z0-9' (required)")
+	if !withKey {
+		return
+	}
+	fs.StringVar(&f.key, "k", "", "the key that will be used in the cipher, written in the alphabet")
+	fs.StringVar(&f.keyMatrix, "key-matrix", "", "the key as a numeric matrix literal like [[3,3],[2,5]], instead of -k")
+	fs.StringVar(&f.keyFile, "key-file", "", "the file with the armored key, instead of -k")
+	fs.StringVar(&f.shift, "s", "", "optional translation vector of the affine cipher, written in the alphabet like the key (not with -key-file, whose key includes it)")
+}
+
+// cipher returns the cipher defined by the flags and its alphabet.
+func (f *cipherFlags) cipher() (*hcipher.Cipher, *hcipher.Alphabet, error) {
+	if f.alphabet == "" {
+		return nil, nil, usageError("missing required -a argument")
+	}
+	alp, err := resolveAlphabet(f.alphabet)
+	if err != nil {
+		return nil, nil, usageError(err.Error())
+	}
+	var opts []hcipher.Option
+	if f.padding != "" {
+		padding, err := parsePadding(f.padding)
+		if err != nil {
+			return nil, nil, usageError(err.Error())
+		}
+		opts = append(opts, hcipher.WithPadding(padding))
+	}
+	c, err := hcipher.NewCipher(alp, opts...)
+	if err != nil {
+		return nil, nil, usageError(err.Error())
+	}
+	return c, alp, nil
+}
+
+// parseKey returns the key given by exactly one of -k, -key-matrix or -key-file, for cipher c
+// whose alphabet is alp.
+func (f *cipherFlags) parseKey(c *hcipher.Cipher, alp *hcipher.Alphabet) (*hcipher.Key, error) {
+	keySources := 0
+	for _, source := range []string{f.key, f.keyMatrix, f.keyFile} {
+		if source != "" {
+			keySources++
+		}
+	}
+	if keySources != 1 {
+		return nil, usageError("exactly one of -k, -key-matrix or -key-file arguments is required")
+	}
+	if f.keyFile != "" && f.shift != "" {
+		return nil, usageError("-s cannot be used with -key-file, armored keys include their translation vector")
+	}
+	var k *hcipher.Key
+	var err error
+	switch {
+	case f.keyMatrix != "":
+		k, err = parseKeyMatrix(f.keyMatrix, f.shift, alp)
+	case f.keyFile != "":
+		var armored []byte
+		if armored, err = ioutil.ReadFile(f.keyFile); err == nil {
+			k, err = c.ParseArmoredKey(armored)
+		}
+	default:
+		if k, err = c.ParseKey(f.key); err == nil && f.shift != "" {
+			var data []int
+			for _, row := range k.Matrix().Data() {
+				data = append(data, row...)
+			}
+			k, err = newKey(data, f.shift, alp)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("got invalid key; %w", err)
+	}
+	return k, nil
+}
+
+// parsePadding returns the padding scheme described by s.
+func parsePadding(s string) (hcipher.Padding, error) {
+	switch {
+	case s == "length":
+		return hcipher.LengthPadding{}, nil
+	case s == "random":
+		return hcipher.RandomPadding{Random: rand.Reader}, nil
+	case strings.HasPrefix(s, "filler:"):
+		filler := []rune(strings.TrimPrefix(s, "filler:"))
+		if len(filler) != 1 {
+			return nil, fmt.Errorf("filler padding requires exactly one symbol, got %q", string(filler))
+		}
+		return hcipher.FillerPadding{Filler: filler[0]}, nil
+	}
+	return nil, fmt.Errorf("got invalid padding scheme %s", s)
+}
+
+// parseKeyMatrix returns the key described by the matrix literal s, e.g. [[3,3],[2,5]]. The
+// translation vector of affine keys is given as symbols of alp.
+func parseKeyMatrix(s, shift string, alp *hcipher.Alphabet) (*hcipher.Key, error) {
+	var rows [][]int
+	if err := json.Unmarshal([]byte(s), &rows); err != nil {
+		return nil, fmt.Errorf("got invalid matrix literal %s; %v", s, err)
+	}
+	var data []int
+	for _, row := range rows {
+		if len(row) != len(rows) {
+			return nil, fmt.Errorf("matrix literal %s is not square", s)
+		}
+		data = append(data, row...)
+	}
+	return newKey(data, shift, alp)
+}
+
+// newKey returns the key with the matrix entries data, and the translation vector shift given as
+// symbols of alp unless it's empty.
+func newKey(data []int, shift string, alp *hcipher.Alphabet) (*hcipher.Key, error) {
+	mod := len(alp.Symbols())
+	if shift == "" {
+		return hcipher.NewKey(data, mod)
+	}
+	var b []int
+	for i, r := range []rune(shift) {
+		x, err := alp.Stoi(r)
+		if err != nil {
+			err = &hcipher.SymbolError{Symbol: r, Position: i}
+			return nil, fmt.Errorf("translation vector %q does not belong to alphabet; %w", shift, err)
+		}
+		b = append(b, x)
+	}
+	return hcipher.NewAffineKey(data, b, mod)
+}
+
+// keySpace describes the number of keys of the given order modulo mod.
+func keySpace(order, mod int) string {
+	return fmt.Sprintf("%s valid keys of order %d modulo %d (%.1f bits)",
+		hcipher.KeySpaceSize(order, mod), order, mod, hcipher.KeySpaceBits(order, mod))
+}
+
+// resolveAlphabet returns the registered alphabet named s (see hcipher.AlphabetNames), or the
+// alphabet defined by s, e.g. A-Z0-9, if there's none.
+func resolveAlphabet(s string) (*hcipher.Alphabet, error) {
+	if alp, err := hcipher.AlphabetByName(s); err == nil {
+		return alp, nil
+	}
+	return hcipher.ParseAlphabet(s)
+}
+
+// runEncrypt executes the encrypt command.
+func runEncrypt(args []string) error {
+	return runCipher("encrypt", args)
+}
+
+// runDecrypt executes the decrypt command.
+func runDecrypt(args []string) error {
+	return runCipher("decrypt", args)
+}
+
+// runCipher executes the encrypt or decrypt command, given by name.
+func runCipher(name string, args []string) error {
+	fs := newFlagSet(name, "-a ALPHABET (-k KEY | -key-matrix MATRIX | -key-file FILE) [flags]",
+		"Transforms the message with the Hill cipher and writes the result, or every step of the\n"+
+			"computation with -explain. Messages are transformed block by block as they are read, so if\n"+
+			"the message is invalid the standard output may already hold the blocks before the error;\n"+
+			"-out is only written when the whole message is valid.")
+	var cf cipherFlags
+	var iof ioFlags
+	cf.register(fs, true)
+	fs.StringVar(&cf.padding, "p", "", "optional padding scheme, either 'filler:SYMBOL', 'length' or 'random'")
+	iof.register(fs, "message")
+	explain := fs.Bool("explain", false, "explain every step of the computation instead of writing only the result")
+	format := fs.String("format", "text", "the output format of -explain, either 'text', 'latex' or 'markdown'")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "latex" && *format != "markdown" {
+		return usageError(fmt.Sprintf("got invalid explanation format %s", *format))
+	}
+
+	c, alp, err := cf.cipher()
+	if err != nil {
+		return err
+	}
+	k, err := cf.parseKey(c, alp)
+	if err != nil {
+		return err
+	}
+	// Random padding prefixes the whole message, so it is the only one that can't be streamed
+	if !*explain && cf.padding != "random" {
+		return streamCipher(c, k, name == "encrypt", &iof)
+	}
+	text, err := iof.read()
+	if err != nil {
+		return err
+	}
+
+	op, trace := c.EncryptWithKey, c.TraceEncrypt
+	if name == "decrypt" {
+		op, trace = c.DecryptWithKey, c.TraceDecrypt
+	}
+	if !*explain {
+		result, err := op(text, k)
+		if err != nil {
+			return err
+		}
+		return iof.write(result + "\n")
+	}
+	t, err := trace(text, k)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "latex":
+		return iof.write(t.LaTeX())
+	case "markdown":
+		return iof.write(t.Markdown())
+	}
+	return iof.write(t.String())
+}
+
+// streamCipher encrypts or decrypts the input into the output block by block, so messages of any
+// size are transformed with constant memory.
+func streamCipher(c *hcipher.Cipher, k *hcipher.Key, encrypt bool, iof *ioFlags) error {
+	in, err := iof.input()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, finish, err := iof.output()
+	if err != nil {
+		return err
+	}
+	err = transformStream(c, k, encrypt, in, out)
+	if err == nil {
+		if _, err = fmt.Fprintln(out); err != nil {
+			err = fmt.Errorf("failed to write output; %v", err)
+		}
+	}
+	return finish(err)
+}
+
+// transformStream copies r into w encrypting or decrypting it with key k.
+func transformStream(c *hcipher.Cipher, k *hcipher.Key, encrypt bool, r io.Reader, w io.Writer) error {
+	if !encrypt {
+		d, err := c.NewDecryptReaderWithKey(r, k)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, d)
+		return err
+	}
+	e, err := c.NewEncryptWriterWithKey(w, k)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(e, r); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// runKeygen executes the keygen command.
+func runKeygen(args []string) error {
+	fs := newFlagSet("keygen", "-a ALPHABET -n ORDER [flags]",
+		"Generates a random key of the given order that is invertible modulo the size of the alphabet.")
+	var cf cipherFlags
+	var iof ioFlags
+	cf.register(fs, false)
+	fs.StringVar(&iof.out, "out", "", "the file to write the key to, instead of the standard output")
+	order := fs.Int("n", 0, "the order of the generated key (required)")
+	armor := fs.Bool("armor", false, "write the key in the armored format read by -key-file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *order == 0 {
+		return usageError("missing required -n argument")
+	}
+
+	c, alp, err := cf.cipher()
+	if err != nil {
+		return err
+	}
+	k, err := hcipher.GenerateKey(*order, alp, rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key; %v", err)
+	}
+	if alp.HasPrimeSize() {
+		fmt.Fprintf(os.Stderr, "note: alphabet size %d is prime, every matrix with non-zero determinant is a valid key\n", len(alp.Symbols()))
+	}
+	fmt.Fprintf(os.Stderr, "note: there are %s\n", keySpace(*order, len(alp.Symbols())))
+	if *armor {
+		armored, _ := c.ArmorKey(k) // Neglect error since key is invertible
+		return iof.write(string(armored))
+	}
+	rawKey, _ := alp.KeyString(k) // Neglect error since entries are residues
+	return iof.write(rawKey + "\n")
+}
+
+// runInspectKey executes the inspect-key command.
+func runInspectKey(args []string) error {
+	fs := newFlagSet("inspect-key", "-a ALPHABET (-k KEY | -key-matrix MATRIX | -key-file FILE) [flags]",
+		"Shows the matrix of a key, its determinant and its inverse modulo the size of the alphabet.")
+	var cf cipherFlags
+	var iof ioFlags
+	cf.register(fs, true)
+	fs.StringVar(&iof.out, "out", "", "the file to write the report to, instead of the standard output")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	c, alp, err := cf.cipher()
+	if err != nil {
+		return err
+	}
+	k, err := cf.parseKey(c, alp)
+	if err != nil {
+		return err
+	}
+	mod := len(alp.Symbols())
+	m := k.Matrix()
+	inv, _ := k.Inverse(mod)                      // Neglect error since key was verified
+	det, _ := m.DeterminantMod(mod)               // Neglect error since order >= 2 and mod >= 2
+	detInv, _ := hcipher.ModularInverse(det, mod) // Neglect error since det is a unit of invertible keys
+	rawKey, _ := alp.KeyString(k)                 // Neglect error since entries are residues
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Key: %s\n", rawKey)
+	fmt.Fprintf(&b, "Order: %d\n", m.Order())
+	fmt.Fprintf(&b, "Modulus: %d\n", mod)
+	fmt.Fprintf(&b, "Alphabet: %s\n", alp.Fingerprint())
+	if shift := k.Shift(); shift != nil {
+		fmt.Fprintf(&b, "Shift: %v\n", shift)
+	}
+	fmt.Fprintf(&b, "Matrix:\n%s", m)
+	fmt.Fprintf(&b, "Determinant mod %d: %d\n", mod, det)
+	fmt.Fprintf(&b, "Determinant inverse mod %d: %d\n", mod, detInv)
+	fmt.Fprintf(&b, "Inverse mod %d:\n%s", mod, inv.Matrix())
+	fmt.Fprintf(&b, "Key space: %s\n", keySpace(m.Order(), mod))
+	return iof.write(b.String())
+}
+
+// runAttack executes the attack command.
+func runAttack(args []string) error {
+	fs := newFlagSet("attack", "-a ALPHABET -n ORDER [flags]",
+		"Recovers the key of a ciphertext. With -known-plaintext the key is solved from the plaintext\n"+
+			"of the ciphertext, otherwise the candidate keys whose decryption reads the most like the\n"+
+			"language are listed, best first.")
+	var cf cipherFlags
+	var iof ioFlags
+	cf.register(fs, false)
+	iof.register(fs, "ciphertext")
+	order := fs.Int("n", 0, "the order of the key (required)")
+	plaintext := fs.String("known-plaintext", "", "the plaintext of the ciphertext, for a known-plaintext attack")
+	plaintextFile := fs.String("known-plaintext-file", "", "the file to read the plaintext of the ciphertext from, instead of -known-plaintext")
+	language := fs.String("language", "english", "the language of the plaintext in a ciphertext-only attack, either 'english' or 'spanish'")
+	candidates := fs.Int("candidates", 5, "the number of candidate keys listed by a ciphertext-only attack")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *order == 0 {
+		return usageError("missing required -n argument")
+	}
+	if *plaintext != "" && *plaintextFile != "" {
+		return usageError("only one of -known-plaintext or -known-plaintext-file arguments can be used")
+	}
+	models := map[string]func() attack.LanguageModel{"english": attack.English, "spanish": attack.Spanish}
+	model, found := models[*language]
+	if !found {
+		return usageError(fmt.Sprintf("got invalid language %s", *language))
+	}
+
+	_, alp, err := cf.cipher()
+	if err != nil {
+		return err
+	}
+	ciphertext, err := iof.read()
+	if err != nil {
+		return err
+	}
+	if *plaintextFile != "" {
+		// The plaintext file is read like -in, without its trailing line break
+		if *plaintext, err = (&ioFlags{in: *plaintextFile}).read(); err != nil {
+			return err
+		}
+	}
+
+	if *plaintext != "" {
+		k, err := attack.RecoverKeyKnownPlaintext(alp, *plaintext, ciphertext, *order)
+		if err != nil {
+			return err
+		}
+		rawKey, _ := alp.KeyString(k) // Neglect error since entries are residues
+		return iof.write(rawKey + "\n")
+	}
+	results, err := attack.RecoverKeyCiphertextOnly(alp, ciphertext, *order, model(), *candidates)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, candidate := range results {
+		rawKey, _ := alp.KeyString(candidate.Key) // Neglect error since entries are residues
+		fmt.Fprintf(&b, "%s\t%.4f\t%s\n", rawKey, candidate.Score, candidate.Preview)
+	}
+	return iof.write(b.String())
+}
@@ -3,3 +3,5 @@ module github.com/pablotrinidad/hillcipher/cli
 go 1.14
 
 require github.com/pablotrinidad/hillcipher/cipher v0.0.0-20200314234624-639ffc5b1ce8
+
+replace github.com/pablotrinidad/hillcipher/cipher => ../cipher
@@ -1,79 +1,137 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	hcipher "github.com/pablotrinidad/hillcipher/cipher"
 )
 
-// mode controls the CLI operation mode, either encryption or decryption.
-type mode uint8
-
+// Exit codes of the CLI
 const (
-	modeUnknown mode = 0
-	modeEncrypt mode = 1
-	modeDecrypt mode = 2
+	exitOK            = 0
+	exitError         = 1
+	exitUsage         = 2
+	exitNotInvertible = 3
+	exitUnknownSymbol = 4
+	exitLength        = 5
 )
 
-var (
-	text, key, alphabet string
-	excMode             mode
-	validModes          = map[string]mode{
-		"e": modeEncrypt, "encrypt": modeEncrypt,
-		"d": modeDecrypt, "decrypt": modeDecrypt,
-	}
-)
+// validationErrors are the cipher validation errors reported with their own exit code and hint.
+var validationErrors = []struct {
+	err  error
+	code int
+	hint string
+	// commandHints overrides hint for the named commands.
+	commandHints map[string]string
+}{
+	{err: hcipher.ErrNotInvertible, code: exitNotInvertible, hint: "the determinant of the key must be coprime with the size of the alphabet"},
+	{err: hcipher.ErrSymbolNotInAlphabet, code: exitUnknownSymbol, hint: "every symbol must belong to the alphabet given by -a"},
+	{err: hcipher.ErrLengthNotMultiple, code: exitLength, hint: "pad the message with -p", commandHints: map[string]string{
+		"decrypt": "the ciphertext is incomplete or corrupt, its length must be multiple of the key's order",
+		"attack":  "the length of the texts must be multiple of the key order given by -n",
+	}},
+}
 
-func init() {
-	flag.StringVar(&text, "t", "", "the text that will be used in the cipher")
-	flag.StringVar(&key, "k", "", "the key that will be used in the cipher")
-	flag.StringVar(&alphabet, "a", "", "the alphabet that will be used in the cipher")
-	flagMode := flag.String("m", "", "the cipher mode, either 'encrypt'/'e' or 'decrypt'/'d'")
+// command is a CLI subcommand.
+type command struct {
+	name, summary string
+	// run executes the command with its arguments, excluding the command name.
+	run func(args []string) error
+}
 
-	flag.Parse()
+// commands lists the CLI subcommands in the order they are shown in the help text.
+var commands = []command{
+	{name: "encrypt", summary: "encrypt a message", run: runEncrypt},
+	{name: "decrypt", summary: "decrypt a message", run: runDecrypt},
+	{name: "keygen", summary: "generate a random key", run: runKeygen},
+	{name: "inspect-key", summary: "show the properties of a key", run: runInspectKey},
+	{name: "attack", summary: "recover a key from a ciphertext", run: runAttack},
+}
 
-	flagsSet := true
-	flag.VisitAll(func(f *flag.Flag) {
-		if f.Value.String() == "" {
-			flagsSet = false
-			fmt.Fprintf(os.Stderr, "missing required -%s argument (%s)\n", f.Name, f.Usage)
-		}
-	})
-	if !flagsSet {
-		os.Exit(2)
-	}
+// usageError is an error caused by invalid command line arguments. It is empty if the error was
+// already reported, e.g. by the flag package.
+type usageError string
 
-	if _, found := validModes[*flagMode]; !found {
-		fmt.Fprintf(os.Stderr, "got invalid cipher mode %s", *flagMode)
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// usage writes the CLI help text to w.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: hillcipher <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
 	}
-	excMode = validModes[*flagMode]
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'hillcipher <command> -h' for the flags of each command. Messages are read from -t,")
+	fmt.Fprintln(w, "-in FILE or the standard input, and results are written to -out FILE or the standard output.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit status is 0 on success, 1 if the command fails, 2 on invalid usage, 3 if the key is not")
+	fmt.Fprintln(w, "invertible, 4 if a symbol doesn't belong to the alphabet and 5 if the message length is not")
+	fmt.Fprintln(w, "multiple of the key's order.")
 }
 
-func main() {
-	cipher, err := hcipher.NewCipher(hcipher.NewAlphabet(alphabet))
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+// run executes the command named by the first argument and returns the exit code.
+func run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+	name, args := args[0], args[1:]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) == 0 {
+			usage(os.Stdout)
+			return exitOK
+		}
+		// Show the help of the given command
+		name, args = args[0], []string{"-h"}
 	}
 
-	var op func(string, string) (string, error)
-	switch excMode {
-	case modeEncrypt:
-		op = cipher.Encrypt
-	case modeDecrypt:
-		op = cipher.Decrypt
-	default:
-		// This is impossible since flags are parsed at the begining
-		fmt.Fprintf(os.Stderr, "got invalid execution mode %v\n", excMode)
-		os.Exit(1)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "hillcipher: unknown command %q\n\n", name)
+		usage(os.Stderr)
+		return exitUsage
 	}
 
-	result, err := op(text, key)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "an error occurred during cipher execution\n%v", err)
-		os.Exit(1)
+	err := cmd.run(args)
+	var uerr usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &uerr):
+		if uerr != "" {
+			fmt.Fprintf(os.Stderr, "hillcipher %s: %v\nRun 'hillcipher %s -h' for usage.\n", name, uerr, name)
+		}
+		return exitUsage
 	}
-	fmt.Fprintln(os.Stdout, result)
+	fmt.Fprintf(os.Stderr, "hillcipher %s: %s\n", name, strings.TrimSpace(err.Error()))
+	for _, v := range validationErrors {
+		if errors.Is(err, v.err) {
+			hint, found := v.commandHints[name]
+			if !found {
+				hint = v.hint
+			}
+			fmt.Fprintf(os.Stderr, "hint: %s\n", hint)
+			return v.code
+		}
+	}
+	return exitError
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
 }
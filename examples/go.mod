@@ -6,3 +6,5 @@ require (
 	github.com/gookit/color v1.2.3
 	github.com/pablotrinidad/hillcipher/cipher v0.0.0-20200314234624-639ffc5b1ce8
 )
+
+replace github.com/pablotrinidad/hillcipher/cipher => ../cipher
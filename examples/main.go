@@ -6,6 +6,7 @@ import (
 
 	"github.com/gookit/color"
 	hcipher "github.com/pablotrinidad/hillcipher/cipher"
+	"github.com/pablotrinidad/hillcipher/cipher/attack"
 )
 
 type keyTextPair [2]string
@@ -17,7 +18,7 @@ func main() {
 	}{
 		{
 			name: "Spanish alphabet (uppercase) without diacritics",
-			alphabet: "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+			alphabet: hcipher.SpanishSymbols,
 			samples: []keyTextPair{
 				keyTextPair{"FORTALEZA", "CONSUL"}, // N: 3
 				keyTextPair{"FORTALEZA", "UUNAMFCIENCIASS"}, // N: 3
@@ -29,7 +30,7 @@ func main() {
 		},
 		{
 			name: "Binary alphabet",
-			alphabet: "01",
+			alphabet: hcipher.BinarySymbols,
 			samples: []keyTextPair{
 				keyTextPair{"1011", "0110101100101101"},
 				keyTextPair{"0000101111100011100111010", "11111"},
@@ -98,4 +99,61 @@ func main() {
 			fmt.Println()
 		}
 	}
-}
\ No newline at end of file
+	knownPlaintextAttack()
+	fmt.Println()
+	ciphertextOnlyAttack()
+}
+
+// knownPlaintextAttack shows how an attacker that knows a plaintext and its ciphertext recovers the key
+func knownPlaintextAttack() {
+	color.Bold.Println("Known-plaintext attack")
+
+	alphabet, _ := hcipher.AlphabetByName("spanish") // Neglect error since spanish is predefined
+	cipher, _ := hcipher.NewCipher(alphabet)
+	key, msg := "FORTALEZA", "UUNAMFCIENCIASS"
+	cipherText, err := cipher.Encrypt(msg, key)
+	if err != nil {
+		color.Comment.Printf("\tFailed to encrypt %q using %q: ", msg, key)
+		fmt.Printf("%s\n", err)
+		return
+	}
+	fmt.Printf("\tE(msg:%q, key:%q) = %s\n", msg, key, cipherText)
+
+	recovered, err := attack.RecoverKeyKnownPlaintext(alphabet, msg, cipherText, 3)
+	if err != nil {
+		color.Comment.Printf("\tFailed to recover key from %q and %q: ", msg, cipherText)
+		fmt.Printf("%s\n", err)
+		return
+	}
+	rawKey, _ := alphabet.KeyString(recovered)
+	color.Success.Println("\tSUCCESS")
+	fmt.Printf("\tRecovered key %q\n%s", rawKey, recovered)
+}
+
+// ciphertextOnlyAttack shows how an attacker that only knows a ciphertext ranks candidate keys
+func ciphertextOnlyAttack() {
+	color.Bold.Println("Ciphertext-only attack")
+
+	alphabet, _ := hcipher.AlphabetByName("spanish") // Neglect error since spanish is predefined
+	cipher, _ := hcipher.NewCipher(alphabet)
+	key := "IKEY"
+	msg := "ENUNLUGARDELAMANCHADECUYONOMBRENOQUIEROACORDARNOHAMUCHOTIEMPOQUEVIVIAUNHIDALGO"
+	cipherText, err := cipher.Encrypt(msg, key)
+	if err != nil {
+		color.Comment.Printf("\tFailed to encrypt %q using %q: ", msg, key)
+		fmt.Printf("%s\n", err)
+		return
+	}
+	fmt.Printf("\tE(msg:%q, key:%q) = %s\n", msg, key, cipherText)
+
+	candidates, err := attack.RecoverKeyCiphertextOnly(alphabet, cipherText, 2, attack.Spanish(), 3)
+	if err != nil {
+		color.Comment.Printf("\tFailed to recover key from %q: ", cipherText)
+		fmt.Printf("%s\n", err)
+		return
+	}
+	for i, c := range candidates {
+		rawKey, _ := alphabet.KeyString(c.Key)
+		fmt.Printf("\t%d) key:%q score:%.3f preview:%s\n", i+1, rawKey, c.Score, c.Preview)
+	}
+}